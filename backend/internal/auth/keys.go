@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FirebaseJWKSURL publishes the keys that sign Firebase Auth ID tokens
+const FirebaseJWKSURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+// ErrUnknownKey is returned by a KeySource that has no key with the requested ID
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySource looks up the RSA public key a token was signed with by its key ID (the JWT "kid" header).
+// HTTPKeySource is used in production; StaticKeySource serves a fixed key set for tests and local development.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+// StaticKeySource is a fixed set of keys indexed by key ID
+type StaticKeySource map[string]*rsa.PublicKey
+
+// Key returns the key with the given ID
+func (s StaticKeySource) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// LoadKeySetFile reads a JWKS document from disk
+func LoadKeySetFile(path string) (StaticKeySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeySet(data)
+}
+
+// ParseKeySet decodes the RSA keys in a JWKS document; keys of other types are skipped
+func ParseKeySet(data []byte) (StaticKeySource, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(StaticKeySource)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus for key %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent for key %s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no RSA keys")
+	}
+	return keys, nil
+}
+
+// HTTPKeySource fetches a JWKS document and caches it for as long as the response's Cache-Control max-age allows.
+// An unknown key ID triggers an early refetch so rotated keys are picked up before the cache expires,
+// rate limited to one refetch per minRefresh so garbage tokens can't hammer the endpoint.
+type HTTPKeySource struct {
+	url        string
+	client     *http.Client
+	minRefresh time.Duration
+
+	mu          sync.Mutex
+	keys        StaticKeySource
+	expiresAt   time.Time
+	lastFetchAt time.Time
+}
+
+// NewHTTPKeySource creates a key source for the JWKS document at url
+func NewHTTPKeySource(url string) *HTTPKeySource {
+	return &HTTPKeySource{
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		minRefresh: time.Minute,
+	}
+}
+
+// Key returns the key with the given ID, refreshing the cached key set when it has expired or lacks the key
+func (s *HTTPKeySource) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if key, ok := s.keys[kid]; ok && now.Before(s.expiresAt) {
+		return key, nil
+	}
+	if s.keys == nil || now.After(s.expiresAt) || now.Sub(s.lastFetchAt) >= s.minRefresh {
+		if err := s.refresh(ctx, now); err != nil {
+			// Keep serving the previous keys if the endpoint is briefly unavailable
+			if key, ok := s.keys[kid]; ok {
+				return key, nil
+			}
+			return nil, err
+		}
+	}
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// refresh refetches the key set. s.mu must be held.
+func (s *HTTPKeySource) refresh(ctx context.Context, now time.Time) error {
+	s.lastFetchAt = now
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching JWKS: unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.expiresAt = now.Add(maxAge(resp.Header.Get("Cache-Control"), time.Hour))
+	return nil
+}
+
+// maxAge returns the max-age directive of a Cache-Control header, or def if there is none
+func maxAge(cacheControl string, def time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return def
+}
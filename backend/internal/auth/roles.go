@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"backend/internal/models"
+)
+
+// Role is a named set of permissions granted to a user
+type Role string
+
+const (
+	RolePartner   Role = "partner"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Permission is a single action a route can require
+type Permission string
+
+const (
+	PermSubmitArticles Permission = "articles:submit"
+	PermReviewQueue    Permission = "moderation:review"
+	PermOverrideScores Permission = "moderation:override"
+	PermManageSources  Permission = "admin:sources"
+	PermManageModels   Permission = "admin:models"
+	PermManageUsers    Permission = "admin:users"
+	PermManageLogging  Permission = "admin:logging"
+)
+
+// rolePermissions lists what each role may do. Admins may do everything.
+var rolePermissions = map[Role][]Permission{
+	RolePartner:   {PermSubmitArticles},
+	RoleModerator: {PermReviewQueue, PermOverrideScores},
+	RoleAdmin: {
+		PermSubmitArticles, PermReviewQueue, PermOverrideScores,
+		PermManageSources, PermManageModels, PermManageUsers, PermManageLogging,
+	},
+}
+
+// Can reports whether the role grants perm
+func (r Role) Can(perm Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Principal is the authenticated caller of a request: a Firebase user or a partner API key
+type Principal struct {
+	// Subject is the Firebase UID, or "partner_key:<id>" for API keys
+	Subject string
+	Email   string
+	Roles   []Role
+	// Token is set for Firebase users, PartnerKey for API keys
+	Token      *Token
+	PartnerKey *models.PartnerKey
+}
+
+// PartnerPrincipal returns the principal for a request authenticated with a partner API key
+func PartnerPrincipal(key *models.PartnerKey) *Principal {
+	return &Principal{
+		Subject:    "partner_key:" + key.ID,
+		Roles:      []Role{RolePartner},
+		PartnerKey: key,
+	}
+}
+
+// Can reports whether any of the principal's roles grants perm
+func (p *Principal) Can(perm Permission) bool {
+	for _, role := range p.Roles {
+		if role.Can(perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin reports whether the principal has the admin role
+func (p *Principal) IsAdmin() bool {
+	for _, role := range p.Roles {
+		if role == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Name identifies the principal in logs and audit records: the email when known, otherwise the subject
+func (p *Principal) Name() string {
+	if p.Email != "" {
+		return p.Email
+	}
+	return p.Subject
+}
+
+// RoleStore assigns roles to users outside of their token claims
+type RoleStore interface {
+	Roles(ctx context.Context, token *Token) ([]Role, error)
+}
+
+// StaticRoleStore maps Firebase UIDs or email addresses to roles.
+// Email entries only apply to tokens whose email_verified claim is true.
+type StaticRoleStore map[string][]Role
+
+// Roles returns the roles assigned to the token's UID and verified email
+func (s StaticRoleStore) Roles(ctx context.Context, token *Token) ([]Role, error) {
+	roles := append([]Role(nil), s[token.UID]...)
+	if verified, _ := token.Claims["email_verified"].(bool); verified && token.Email != "" {
+		roles = append(roles, s[strings.ToLower(token.Email)]...)
+	}
+	return roles, nil
+}
+
+// LoadRoleFile reads a JSON object mapping UIDs or email addresses to role lists
+func LoadRoleFile(path string) (StaticRoleStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string][]Role
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid role file: %w", err)
+	}
+
+	store := make(StaticRoleStore, len(raw))
+	for subject, roles := range raw {
+		for _, role := range roles {
+			if _, ok := rolePermissions[role]; !ok {
+				return nil, fmt.Errorf("invalid role file: unknown role %q for %s", role, subject)
+			}
+		}
+		// UIDs are case sensitive; email addresses are matched case-insensitively
+		if strings.Contains(subject, "@") {
+			subject = strings.ToLower(subject)
+		}
+		store[subject] = roles
+	}
+	return store, nil
+}
+
+// RoleResolver determines a verified user's roles from the "roles" (or single "role") custom claim,
+// set with the Firebase Admin SDK, merged with any roles from a local RoleStore
+type RoleResolver struct {
+	store RoleStore
+}
+
+// NewRoleResolver creates a resolver; store may be nil to rely on token claims alone
+func NewRoleResolver(store RoleStore) *RoleResolver {
+	return &RoleResolver{store: store}
+}
+
+// Principal resolves the roles for token and returns the resulting principal
+func (r *RoleResolver) Principal(ctx context.Context, token *Token) (*Principal, error) {
+	roles := claimRoles(token.Claims)
+	if r.store != nil {
+		stored, err := r.store.Roles(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, stored...)
+	}
+
+	return &Principal{
+		Subject: token.UID,
+		Email:   token.Email,
+		Roles:   dedupeRoles(roles),
+		Token:   token,
+	}, nil
+}
+
+// claimRoles reads known roles from the token's custom claims, ignoring anything unrecognised
+func claimRoles(claims map[string]interface{}) []Role {
+	var names []interface{}
+	if list, ok := claims["roles"].([]interface{}); ok {
+		names = list
+	}
+	if single, ok := claims["role"].(string); ok {
+		names = append(names, single)
+	}
+
+	var roles []Role
+	for _, name := range names {
+		role, _ := name.(string)
+		if _, ok := rolePermissions[Role(role)]; ok {
+			roles = append(roles, Role(role))
+		}
+	}
+	return roles
+}
+
+func dedupeRoles(roles []Role) []Role {
+	seen := make(map[Role]bool, len(roles))
+	unique := roles[:0]
+	for _, role := range roles {
+		if !seen[role] {
+			seen[role] = true
+			unique = append(unique, role)
+		}
+	}
+	return unique
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal stored by WithPrincipal
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrMissingToken is returned when a request has no bearer token
+var ErrMissingToken = errors.New("missing bearer token")
+
+// ErrInvalidToken wraps every reason a token was rejected
+var ErrInvalidToken = errors.New("invalid token")
+
+// Token is a verified Firebase ID token
+type Token struct {
+	UID       string
+	Email     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// Claims holds every claim in the payload, including custom claims set with the Admin SDK
+	Claims map[string]interface{}
+}
+
+// Verifier checks the signature and standard claims of Firebase ID tokens
+type Verifier struct {
+	keys     KeySource
+	issuer   string
+	audience string
+	// leeway tolerates clock skew between this server and the token issuer
+	leeway time.Duration
+	now    func() time.Time
+}
+
+// NewFirebaseVerifier creates a verifier for ID tokens issued to the given Firebase project
+func NewFirebaseVerifier(projectID string, keys KeySource) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   "https://securetoken.google.com/" + projectID,
+		audience: projectID,
+		leeway:   time.Minute,
+		now:      time.Now,
+	}
+}
+
+// Verify parses token and checks its RS256 signature, issuer, audience, subject and validity period
+func (v *Verifier) Verify(ctx context.Context, token string) (*Token, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalid("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalid("malformed header")
+	}
+	if header.Alg != "RS256" {
+		return nil, invalid("unexpected signing algorithm %q", header.Alg)
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if errors.Is(err, ErrUnknownKey) {
+		return nil, invalid("unknown key ID %q", header.Kid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid("malformed signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, invalid("bad signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalid("malformed payload")
+	}
+	return v.checkClaims(claims)
+}
+
+func (v *Verifier) checkClaims(claims map[string]interface{}) (*Token, error) {
+	now := v.now()
+
+	if iss, _ := claims["iss"].(string); iss != v.issuer {
+		return nil, invalid("unexpected issuer %q", iss)
+	}
+	if !hasAudience(claims["aud"], v.audience) {
+		return nil, invalid("unexpected audience")
+	}
+	sub, _ := claims["sub"].(string)
+	if sub == "" || len(sub) > 128 {
+		return nil, invalid("missing or oversized subject")
+	}
+
+	exp, ok := numericTime(claims["exp"])
+	if !ok {
+		return nil, invalid("missing expiry")
+	}
+	if now.After(exp.Add(v.leeway)) {
+		return nil, invalid("token expired")
+	}
+	iat, ok := numericTime(claims["iat"])
+	if !ok || iat.After(now.Add(v.leeway)) {
+		return nil, invalid("issued in the future")
+	}
+	if authTime, ok := numericTime(claims["auth_time"]); ok && authTime.After(now.Add(v.leeway)) {
+		return nil, invalid("authenticated in the future")
+	}
+
+	email, _ := claims["email"].(string)
+	return &Token{
+		UID:       sub,
+		Email:     email,
+		IssuedAt:  iat,
+		ExpiresAt: exp,
+		Claims:    claims,
+	}, nil
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidToken, fmt.Sprintf(format, args...))
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// hasAudience reports whether the aud claim, a string or an array of strings, contains audience
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// numericTime converts a JWT NumericDate claim to a time
+func numericTime(v interface{}) (time.Time, bool) {
+	seconds, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value
+func BearerToken(header string) (string, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", ErrMissingToken
+	}
+	return strings.TrimSpace(token), nil
+}
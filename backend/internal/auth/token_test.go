@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testProject = "fire-test"
+
+var testNow = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	return key
+}
+
+// signToken builds a JWT with the given header and claims, signed with RS256 by key
+func signToken(t *testing.T, key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	t.Helper()
+	segment := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("encoding token: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(header) + "." + segment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// validClaims are the claims of a Firebase ID token for testProject issued a minute before testNow
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":       "https://securetoken.google.com/" + testProject,
+		"aud":       testProject,
+		"sub":       "user-1",
+		"email":     "moderator@example.com",
+		"iat":       testNow.Add(-time.Minute).Unix(),
+		"auth_time": testNow.Add(-time.Minute).Unix(),
+		"exp":       testNow.Add(time.Hour).Unix(),
+	}
+}
+
+func newTestVerifier(keys KeySource) *Verifier {
+	v := NewFirebaseVerifier(testProject, keys)
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	key := generateKey(t)
+	other := generateKey(t)
+	v := newTestVerifier(StaticKeySource{"key-1": &key.PublicKey})
+	header := map[string]interface{}{"alg": "RS256", "kid": "key-1", "typ": "JWT"}
+
+	with := func(name string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		claims[name] = value
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: signToken(t, key, header, validClaims())},
+		{name: "audience in a list", token: signToken(t, key, header, with("aud", []string{"other", testProject}))},
+		{name: "expired within leeway", token: signToken(t, key, header, with("exp", testNow.Add(-30*time.Second).Unix()))},
+		{name: "issued slightly in the future", token: signToken(t, key, header, with("iat", testNow.Add(30*time.Second).Unix()))},
+		{name: "expired beyond leeway", token: signToken(t, key, header, with("exp", testNow.Add(-2*time.Minute).Unix())), wantErr: true},
+		{name: "issued in the future", token: signToken(t, key, header, with("iat", testNow.Add(2*time.Minute).Unix())), wantErr: true},
+		{name: "wrong issuer", token: signToken(t, key, header, with("iss", "https://securetoken.google.com/other")), wantErr: true},
+		{name: "wrong audience", token: signToken(t, key, header, with("aud", "other")), wantErr: true},
+		{name: "missing subject", token: signToken(t, key, header, with("sub", "")), wantErr: true},
+		{name: "missing expiry", token: signToken(t, key, header, with("exp", nil)), wantErr: true},
+		{name: "HS256", token: signToken(t, key, map[string]interface{}{"alg": "HS256", "kid": "key-1"}, validClaims()), wantErr: true},
+		{name: "none", token: signToken(t, key, map[string]interface{}{"alg": "none", "kid": "key-1"}, validClaims()), wantErr: true},
+		{name: "unknown kid", token: signToken(t, key, map[string]interface{}{"alg": "RS256", "kid": "key-2"}, validClaims()), wantErr: true},
+		{name: "signed by another key", token: signToken(t, other, header, validClaims()), wantErr: true},
+		{name: "malformed", token: "not.a-token", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := v.Verify(context.Background(), tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if token.UID != "user-1" || token.Email != "moderator@example.com" {
+				t.Errorf("Verify() = UID %q, email %q", token.UID, token.Email)
+			}
+		})
+	}
+}
+
+func TestVerifyTamperedPayload(t *testing.T) {
+	key := generateKey(t)
+	v := newTestVerifier(StaticKeySource{"key-1": &key.PublicKey})
+	token := signToken(t, key, map[string]interface{}{"alg": "RS256", "kid": "key-1"}, validClaims())
+
+	forged := signToken(t, key, map[string]interface{}{"alg": "RS256", "kid": "key-1"}, map[string]interface{}{"sub": "admin"})
+	parts, forgedParts := strings.Split(token, "."), strings.Split(forged, ".")
+	tampered := parts[0] + "." + forgedParts[1] + "." + parts[2]
+
+	if _, err := v.Verify(context.Background(), tampered); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+	}
+}
+
+// jwksServer serves a JWKS document holding the current keys and counts how often it is fetched
+type jwksServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetches int
+}
+
+func newJWKSServer(t *testing.T, keys map[string]*rsa.PublicKey) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetches++
+
+		type jwk struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		}
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+		for kid, key := range s.keys {
+			set.Keys = append(set.Keys, jwk{
+				Kty: "RSA",
+				Kid: kid,
+				Alg: "RS256",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) rotate(keys map[string]*rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func TestHTTPKeySourceRefetchesOnRotation(t *testing.T) {
+	oldKey, newKey := generateKey(t), generateKey(t)
+	server := newJWKSServer(t, map[string]*rsa.PublicKey{"old": &oldKey.PublicKey})
+	keys := NewHTTPKeySource(server.URL)
+	keys.minRefresh = 0
+	v := newTestVerifier(keys)
+	ctx := context.Background()
+
+	oldToken := signToken(t, oldKey, map[string]interface{}{"alg": "RS256", "kid": "old"}, validClaims())
+	for i := 0; i < 2; i++ {
+		if _, err := v.Verify(ctx, oldToken); err != nil {
+			t.Fatalf("Verify() with the published key: %v", err)
+		}
+	}
+	if got := server.fetchCount(); got != 1 {
+		t.Fatalf("key set fetched %d times, want 1 while cached", got)
+	}
+
+	server.rotate(map[string]*rsa.PublicKey{"new": &newKey.PublicKey})
+	newToken := signToken(t, newKey, map[string]interface{}{"alg": "RS256", "kid": "new"}, validClaims())
+	if _, err := v.Verify(ctx, newToken); err != nil {
+		t.Fatalf("Verify() with a rotated key: %v", err)
+	}
+	if got := server.fetchCount(); got != 2 {
+		t.Fatalf("key set fetched %d times, want a refetch for the unknown key", got)
+	}
+}
+
+func TestHTTPKeySourceLimitsRefetches(t *testing.T) {
+	key := generateKey(t)
+	server := newJWKSServer(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey})
+	keys := NewHTTPKeySource(server.URL)
+	ctx := context.Background()
+
+	if _, err := keys.Key(ctx, "key-1"); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		kid := fmt.Sprintf("garbage-%d", i)
+		if _, err := keys.Key(ctx, kid); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("Key(%q) error = %v, want ErrUnknownKey", kid, err)
+		}
+	}
+	if got := server.fetchCount(); got != 1 {
+		t.Fatalf("key set fetched %d times, want unknown key IDs not to refetch within minRefresh", got)
+	}
+}
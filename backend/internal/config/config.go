@@ -0,0 +1,440 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"backend/internal/logging"
+	"backend/internal/ratelimit"
+)
+
+// DefaultPath is read when Load is given no path and the file exists
+const DefaultPath = "config.yaml"
+
+// Config is the backend's effective configuration
+type Config struct {
+	Port int `yaml:"port"`
+	// CORSOrigins are the browser origins allowed to call the API; "*" allows any
+	CORSOrigins []string `yaml:"cors_origins"`
+	// FirebaseProjectID is the Firestore project and the audience of accepted ID tokens
+	FirebaseProjectID string `yaml:"firebase_project_id"`
+
+	Log        LogConfig         `yaml:"log"`
+	Tracing    TracingConfig     `yaml:"tracing"`
+	Server     ServerConfig      `yaml:"server"`
+	Store      StoreConfig       `yaml:"store"`
+	Auth       AuthConfig        `yaml:"auth"`
+	ML         MLConfig          `yaml:"ml"`
+	Jobs       JobsConfig        `yaml:"jobs"`
+	Thresholds ThresholdsConfig  `yaml:"thresholds"`
+	RateLimits map[string]string `yaml:"rate_limits"`
+}
+
+// LogConfig configures structured logging
+type LogConfig struct {
+	// Level is the initial minimum level (debug, info, warn or error); admins can change it while running
+	Level string `yaml:"level"`
+}
+
+// TracingConfig configures OpenTelemetry span export
+type TracingConfig struct {
+	// Exporter is none, stdout or otlp
+	Exporter string `yaml:"exporter"`
+	// OTLPEndpoint is the collector's OTLP/HTTP URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// SampleRatio is the fraction of new traces recorded
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// ServerConfig holds the HTTP server's timeouts and the proxies it sits behind
+type ServerConfig struct {
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	// WriteTimeout bounds a whole request, so it must leave room for synchronous scoring
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout is how long in-flight requests and queued jobs get to finish on SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// HealthCheckTimeout limits each dependency check in GET /health/ready
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
+	// TrustedProxies are the IP addresses or CIDR ranges of reverse proxies whose X-Forwarded-For is believed
+	// when identifying clients; empty means every client is identified by its connection's address
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// StoreConfig selects the article store
+type StoreConfig struct {
+	// Backend is firestore, sql or memory
+	Backend string `yaml:"backend"`
+	// DatabaseURL is a postgres:// DSN or a SQLite file path for the sql backend
+	DatabaseURL string `yaml:"database_url"`
+}
+
+// AuthConfig configures ID token verification and role assignment
+type AuthConfig struct {
+	Disabled  bool   `yaml:"disabled"`
+	JWKSURL   string `yaml:"jwks_url"`
+	JWKSFile  string `yaml:"jwks_file"`
+	RolesFile string `yaml:"roles_file"`
+}
+
+// MLConfig configures the Python prediction workers
+type MLConfig struct {
+	// PythonPath is the interpreter; empty picks python3 or python from PATH
+	PythonPath string `yaml:"python_path"`
+	// ScriptPath is predict.py; empty means ml/predict.py under the working directory
+	ScriptPath string `yaml:"script_path"`
+	// Registry is the manifest of model versions to load; empty means models.yaml next to predict.py
+	Registry string `yaml:"registry"`
+	// Aggregation combines the scores of a long article's chunks: mean, min or confidence_weighted
+	Aggregation string `yaml:"aggregation"`
+	// HeadlineWeight is the share of an article's score given to its headline, the rest going to its body
+	HeadlineWeight float64       `yaml:"headline_weight"`
+	Workers        int           `yaml:"workers"`
+	Timeout        time.Duration `yaml:"timeout"`
+	// ExplanationTimeout limits computing an explanation, which runs the model once per word
+	ExplanationTimeout time.Duration `yaml:"explanation_timeout"`
+	// ShadowMaxPending caps the shadow version's predictions in flight; articles beyond it aren't scored in shadow
+	ShadowMaxPending int `yaml:"shadow_max_pending"`
+}
+
+// JobsConfig configures the background queue for async submissions
+type JobsConfig struct {
+	Workers   int `yaml:"workers"`
+	QueueSize int `yaml:"queue_size"`
+	// Retention is how long finished jobs can still be polled
+	Retention time.Duration `yaml:"retention"`
+}
+
+// ThresholdsConfig holds the FIRE score cut-offs: scores from Real up are labelled real ("No risk detected"),
+// scores from Unverified up to Real are "Unverified" and anything lower is "Likely misleading"
+type ThresholdsConfig struct {
+	Real       int `yaml:"real"`
+	Unverified int `yaml:"unverified"`
+}
+
+// rateLimitGroups are the route groups a rate limit can be configured for, plus auth, which limits
+// failed authentication attempts per client address
+var rateLimitGroups = []string{"submit", "report", "default", "auth"}
+
+// Default returns the configuration used for anything the file and environment leave unset
+func Default() *Config {
+	return &Config{
+		Port:        8080,
+		CORSOrigins: []string{"http://localhost:3000"},
+		Log:         LogConfig{Level: "info"},
+		Tracing:     TracingConfig{Exporter: "none", SampleRatio: 1},
+		Server: ServerConfig{
+			ReadHeaderTimeout:  10 * time.Second,
+			ReadTimeout:        30 * time.Second,
+			WriteTimeout:       2 * time.Minute,
+			IdleTimeout:        2 * time.Minute,
+			ShutdownTimeout:    30 * time.Second,
+			HealthCheckTimeout: 10 * time.Second,
+		},
+		Store: StoreConfig{Backend: "firestore", DatabaseURL: "fire.db"},
+		ML: MLConfig{
+			Aggregation:    "mean",
+			HeadlineWeight: 0.3,
+			Workers:        2,
+			Timeout:        30 * time.Second,
+
+			ExplanationTimeout: 90 * time.Second,
+			ShadowMaxPending:   100,
+		},
+		Jobs:       JobsConfig{Workers: 2, QueueSize: 100, Retention: time.Hour},
+		Thresholds: ThresholdsConfig{Real: 50, Unverified: 35},
+		RateLimits: map[string]string{
+			"submit":  "60/m,burst=20,key=user",
+			"report":  "10/m,burst=5,key=ip",
+			"default": "600/m,burst=100,key=user",
+			"auth":    "20/m,burst=10,key=ip",
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file at path (DefaultPath when path is empty
+// and that file exists) and then environment variables, and validates the result
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		if _, err := os.Stat(DefaultPath); err == nil {
+			path = DefaultPath
+		}
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	// Report malformed variables together with any invalid settings
+	if err := errors.Join(cfg.applyEnv(), cfg.Validate()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overlays the environment variables documented in the README
+func (c *Config) applyEnv() error {
+	var errs []error
+	envInt("PORT", &c.Port, &errs)
+	envList("CORS_ORIGINS", &c.CORSOrigins)
+	envString("FIREBASE_PROJECT_ID", &c.FirebaseProjectID)
+	envString("LOG_LEVEL", &c.Log.Level)
+	envString("TRACING_EXPORTER", &c.Tracing.Exporter)
+	envString("TRACING_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	envFloat("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio, &errs)
+
+	envDuration("SERVER_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout, &errs)
+	envDuration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout, &errs)
+	envDuration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout, &errs)
+	envDuration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout, &errs)
+	envDuration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout, &errs)
+	envDuration("HEALTH_CHECK_TIMEOUT", &c.Server.HealthCheckTimeout, &errs)
+	envList("TRUSTED_PROXIES", &c.Server.TrustedProxies)
+
+	envString("STORE_BACKEND", &c.Store.Backend)
+	envString("DATABASE_URL", &c.Store.DatabaseURL)
+
+	envBool("AUTH_DISABLED", &c.Auth.Disabled, &errs)
+	envString("AUTH_JWKS_URL", &c.Auth.JWKSURL)
+	envString("AUTH_JWKS_FILE", &c.Auth.JWKSFile)
+	envString("ROLES_FILE", &c.Auth.RolesFile)
+
+	envString("PYTHON_PATH", &c.ML.PythonPath)
+	envString("ML_SCRIPT_PATH", &c.ML.ScriptPath)
+	envString("ML_REGISTRY", &c.ML.Registry)
+	envString("ML_AGGREGATION", &c.ML.Aggregation)
+	envFloat("ML_HEADLINE_WEIGHT", &c.ML.HeadlineWeight, &errs)
+	envInt("ML_WORKERS", &c.ML.Workers, &errs)
+	envDuration("ML_TIMEOUT", &c.ML.Timeout, &errs)
+	envDuration("ML_EXPLANATION_TIMEOUT", &c.ML.ExplanationTimeout, &errs)
+	envInt("ML_SHADOW_MAX_PENDING", &c.ML.ShadowMaxPending, &errs)
+
+	envInt("JOB_WORKERS", &c.Jobs.Workers, &errs)
+	envInt("JOB_QUEUE_SIZE", &c.Jobs.QueueSize, &errs)
+	envDuration("JOB_RETENTION", &c.Jobs.Retention, &errs)
+
+	envInt("SCORE_THRESHOLD_REAL", &c.Thresholds.Real, &errs)
+	envInt("SCORE_THRESHOLD_UNVERIFIED", &c.Thresholds.Unverified, &errs)
+
+	for _, group := range rateLimitGroups {
+		if v := os.Getenv("RATE_LIMIT_" + strings.ToUpper(group)); v != "" {
+			if c.RateLimits == nil {
+				c.RateLimits = make(map[string]string)
+			}
+			c.RateLimits[group] = v
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func envString(key string, dst *string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func envList(key string, dst *[]string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func envInt(key string, dst *int, errs *[]error) {
+	if v := os.Getenv(key); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s %q: must be an integer", key, v))
+			return
+		}
+		*dst = n
+	}
+}
+
+func envFloat(key string, dst *float64, errs *[]error) {
+	if v := os.Getenv(key); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s %q: must be a number", key, v))
+			return
+		}
+		*dst = f
+	}
+}
+
+func envBool(key string, dst *bool, errs *[]error) {
+	if v := os.Getenv(key); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s %q: must be true or false", key, v))
+			return
+		}
+		*dst = b
+	}
+}
+
+func envDuration(key string, dst *time.Duration, errs *[]error) {
+	if v := os.Getenv(key); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s %q: must be a duration such as 30s", key, v))
+			return
+		}
+		*dst = d
+	}
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Port > 0 && c.Port < 65536, "port %d: must be between 1 and 65535", c.Port)
+	check(len(c.CORSOrigins) > 0, "cors_origins: at least one origin is required")
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q: must be none, stdout or otlp", c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio %g: must be between 0 and 1", c.Tracing.SampleRatio)
+
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout %s: must be positive", c.Server.ReadHeaderTimeout)
+	check(c.Server.ReadTimeout > 0, "server.read_timeout %s: must be positive", c.Server.ReadTimeout)
+	check(c.Server.WriteTimeout > c.ML.Timeout, "server.write_timeout %s: must be longer than ml.timeout (%s)", c.Server.WriteTimeout, c.ML.Timeout)
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout %s: must be positive", c.Server.IdleTimeout)
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout %s: must be positive", c.Server.ShutdownTimeout)
+	check(c.Server.HealthCheckTimeout > 0, "server.health_check_timeout %s: must be positive", c.Server.HealthCheckTimeout)
+	for _, proxy := range c.Server.TrustedProxies {
+		_, prefixErr := netip.ParsePrefix(proxy)
+		_, addrErr := netip.ParseAddr(proxy)
+		check(prefixErr == nil || addrErr == nil, "server.trusted_proxies %q: must be an IP address or CIDR range", proxy)
+	}
+
+	switch c.Store.Backend {
+	case "firestore", "memory":
+	case "sql":
+		check(c.Store.DatabaseURL != "", "store.database_url: required for the sql backend")
+	default:
+		errs = append(errs, fmt.Errorf("store.backend %q: must be firestore, sql or memory", c.Store.Backend))
+	}
+	check(c.FirebaseProjectID != "" || c.Auth.Disabled,
+		"firebase_project_id: required to verify ID tokens unless auth.disabled is set")
+	check(c.FirebaseProjectID != "" || c.Store.Backend != "firestore",
+		"firebase_project_id: required for the firestore store")
+
+	switch c.ML.Aggregation {
+	case "mean", "min", "confidence_weighted":
+	default:
+		errs = append(errs, fmt.Errorf("ml.aggregation %q: must be mean, min or confidence_weighted", c.ML.Aggregation))
+	}
+	check(c.ML.HeadlineWeight >= 0 && c.ML.HeadlineWeight <= 1, "ml.headline_weight %g: must be between 0 and 1", c.ML.HeadlineWeight)
+	check(c.ML.Workers > 0, "ml.workers %d: must be positive", c.ML.Workers)
+	check(c.ML.Timeout > 0, "ml.timeout %s: must be positive", c.ML.Timeout)
+	check(c.ML.ExplanationTimeout > 0, "ml.explanation_timeout %s: must be positive", c.ML.ExplanationTimeout)
+	check(c.Server.WriteTimeout > c.ML.ExplanationTimeout, "server.write_timeout %s: must be longer than ml.explanation_timeout (%s)",
+		c.Server.WriteTimeout, c.ML.ExplanationTimeout)
+	check(c.ML.ShadowMaxPending > 0, "ml.shadow_max_pending %d: must be positive", c.ML.ShadowMaxPending)
+	check(c.Jobs.Workers > 0, "jobs.workers %d: must be positive", c.Jobs.Workers)
+	check(c.Jobs.QueueSize > 0, "jobs.queue_size %d: must be positive", c.Jobs.QueueSize)
+	check(c.Jobs.Retention > 0, "jobs.retention %s: must be positive", c.Jobs.Retention)
+
+	check(c.Thresholds.Unverified > 0 && c.Thresholds.Unverified < c.Thresholds.Real && c.Thresholds.Real <= 100,
+		"thresholds: need 0 < unverified (%d) < real (%d) <= 100", c.Thresholds.Unverified, c.Thresholds.Real)
+
+	for group, rule := range c.RateLimits {
+		if !knownRateLimitGroup(group) {
+			errs = append(errs, fmt.Errorf("rate_limits.%s: unknown group (expected %s)", group, strings.Join(rateLimitGroups, ", ")))
+			continue
+		}
+		if rule == "off" {
+			continue
+		}
+		parsed, err := ratelimit.ParseRule(rule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rate_limits.%s: %w", group, err))
+			continue
+		}
+		check(group != "auth" || parsed.Key == ratelimit.KeyIP, "rate_limits.auth: must be keyed by ip, as failed callers have no identity")
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return nil
+}
+
+func knownRateLimitGroup(group string) bool {
+	for _, g := range rateLimitGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// dsnPassword matches the password in key=value DSNs and URL query strings
+var dsnPassword = regexp.MustCompile(`(password=)[^\s&]*`)
+
+// Redacted returns a copy safe to log, with credentials in the database URL masked
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	dsn := c.Store.DatabaseURL
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+			dsn = u.String()
+		}
+	}
+	redacted.Store.DatabaseURL = dsnPassword.ReplaceAllString(dsn, "${1}REDACTED")
+	return &redacted
+}
+
+// String renders the redacted configuration as YAML
+func (c *Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("<unprintable config: %v>", err)
+	}
+	return string(data)
+}
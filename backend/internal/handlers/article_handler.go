@@ -1,46 +1,148 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/sync/singleflight"
 
+	"backend/internal/auth"
+	"backend/internal/metrics"
 	"backend/internal/models"
 	"backend/internal/services"
 )
 
 // ArticleHandler handles article-related HTTP requests
 type ArticleHandler struct {
-	mlService        *services.MLService
-	firestoreService *services.FirestoreService
+	models      *services.ModelRegistry
+	shadow      *services.ShadowScorer
+	store       services.ArticleStore
+	jobs        *services.JobQueue
+	partnerKeys *services.PartnerKeyService
+	thresholds  VersionThresholds
+	// batchTimeout is how long a synchronous batch may take to answer (the server's write timeout), 0 for no limit
+	batchTimeout time.Duration
+
+	// explanations deduplicates concurrent explanation requests by article ID
+	explanations singleflight.Group
 }
 
 // NewArticleHandler creates a new article handler
-func NewArticleHandler(mlService *services.MLService, firestoreService *services.FirestoreService) *ArticleHandler {
+func NewArticleHandler(models *services.ModelRegistry, shadow *services.ShadowScorer, store services.ArticleStore, jobs *services.JobQueue, partnerKeys *services.PartnerKeyService, thresholds VersionThresholds, batchTimeout time.Duration) *ArticleHandler {
 	return &ArticleHandler{
-		mlService:        mlService,
-		firestoreService: firestoreService,
+		models:       models,
+		shadow:       shadow,
+		store:        store,
+		jobs:         jobs,
+		partnerKeys:  partnerKeys,
+		thresholds:   thresholds,
+		batchTimeout: batchTimeout,
 	}
 }
 
 // SubmitArticle handles POST /api/v1/partner/submit
+// With ?async=true the article is queued and 202 Accepted is returned with a job ID to poll.
 func (h *ArticleHandler) SubmitArticle(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateArticleRequest
 
 	// Parse JSON from React frontend
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Failed to decode request: %v", err)
+		slog.InfoContext(r.Context(), "Failed to decode request", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
+	article, err := articleFromRequest(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// API key submissions are limited to the key's sources and count against its daily article quota
+	key := requestPartnerKey(r)
+	if key != nil {
+		if !services.SourceAllowed(key, article.Source) {
+			http.Error(w, fmt.Sprintf("Source %q is not allowed for this API key", article.Source), http.StatusForbidden)
+			return
+		}
+		if !h.reserveArticles(w, r, key, 1) {
+			return
+		}
+		article.PartnerKeyID = key.ID
+	}
+
+	if r.URL.Query().Get("async") == "true" {
+		h.submitArticleAsync(w, r, key, article)
+		return
+	}
+
+	saved := false
+	if key != nil {
+		defer func() {
+			if !saved {
+				h.partnerKeys.ReleaseArticles(r.Context(), key, 1)
+			}
+		}()
+	}
+
+	// Call ML service to get FIRE score
+	slog.DebugContext(r.Context(), "Predicting FIRE score", "source", article.Source, "content_length", len(article.Content))
+	fireScore, err := h.models.PredictFIREScore(r.Context(), article.PredictionInput())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "ML prediction failed", "error", err)
+		var timeoutErr *services.PredictionTimeoutError
+		switch {
+		case errors.As(err, &timeoutErr):
+			http.Error(w, "Timed out calculating FIRE score", http.StatusGatewayTimeout)
+		case errors.Is(err, context.Canceled):
+			// Client went away; nobody is left to read a response
+		default:
+			http.Error(w, "Failed to calculate FIRE score", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	article.FIREScore = fireScore
+	// Save article to the store
+	articleID, err := h.store.SaveArticle(r.Context(), &article)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to save article", "error", err)
+		http.Error(w, "Failed to save article", http.StatusInternalServerError)
+		return
+	}
+
+	saved = true
+	h.observeScore(fireScore)
+	h.shadow.Score(r.Context(), articleID, article.PredictionInput(), fireScore)
+	slog.InfoContext(r.Context(), "Article scored and saved", "article_id", articleID, "fire_score", fireScore.OverallScore)
+
+	// Create response matching frontend expectations
+	response := map[string]interface{}{
+		"article_id": articleID,
+		"fire_score": h.thresholds.fireScoreResponse(fireScore),
+	}
+
+	// Return result to frontend
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to encode response", "error", err)
+	}
+}
+
+// articleFromRequest validates a submission and converts it to an Article
+func articleFromRequest(req *models.CreateArticleRequest) (models.Article, error) {
 	// Validate required fields
 	if req.Title == "" || req.Content == "" || req.Source == "" {
-		http.Error(w, "Missing required fields", http.StatusBadRequest)
-		return
+		return models.Article{}, errors.New("Missing required fields")
 	}
 
 	// Parse published date
@@ -49,111 +151,224 @@ func (h *ArticleHandler) SubmitArticle(w http.ResponseWriter, r *http.Request) {
 		// Try alternative date format
 		publishedAt, err = time.Parse("2006-01-02", req.PublishedAt)
 		if err != nil {
-			log.Printf("Failed to parse date: %v", err)
-			http.Error(w, "Invalid date format", http.StatusBadRequest)
-			return
+			return models.Article{}, errors.New("Invalid date format")
 		}
 	}
 
 	// Create article object
-	article := models.Article{
+	return models.Article{
 		Title:       req.Title,
 		Content:     req.Content,
 		URL:         req.URL,
 		Source:      req.Source,
 		Author:      req.Author,
 		PublishedAt: publishedAt,
+	}, nil
+}
+
+// fireScoreResponse formats a freshly predicted score, including the model's own confidence
+// and the score of each chunk of the content, so it's visible which part of a long article drove the result
+func (v VersionThresholds) fireScoreResponse(fireScore *models.FIREScore) map[string]interface{} {
+	t := v.For(fireScore.ModelVersion)
+	response := map[string]interface{}{
+		"score":         fireScore.OverallScore,
+		"confidence":    fireScore.Confidence,
+		"label":         t.Label(fireScore.OverallScore),
+		"category":      t.Category(fireScore.OverallScore),
+		"model_version": fireScore.ModelVersion,
+	}
+	if fireScore.HeadlineScore != nil {
+		response["headline_score"] = *fireScore.HeadlineScore
 	}
+	if fireScore.BodyScore != nil {
+		response["body_score"] = *fireScore.BodyScore
+	}
+	if len(fireScore.Chunks) > 0 {
+		response["chunks"] = fireScore.Chunks
+		response["aggregation"] = fireScore.Aggregation
+		response["truncated"] = fireScore.Truncated
+	}
+	return response
+}
 
-	// Call ML service to get FIRE score
-	log.Printf("Predicting FIRE score for article: %s", article.Title)
-	fireScore, err := h.mlService.PredictFIREScore(article.Content)
-	if err != nil {
-		log.Printf("ML prediction failed: %v", err)
-		http.Error(w, "Failed to calculate FIRE score", http.StatusInternalServerError)
-		return
+// addSubScores adds the article's stored headline and body scores to response, if it has them
+func addSubScores(response map[string]interface{}, article *models.Article) {
+	if article.HeadlineScore != nil {
+		response["headline_score"] = *article.HeadlineScore
+	}
+	if article.BodyScore != nil {
+		response["body_score"] = *article.BodyScore
 	}
+}
 
-	article.FIREScore = fireScore
-	log.Printf("FIRE score calculated: %d", fireScore.OverallScore)
+// observeScore adds a newly scored and saved article to the FIRE score distribution
+func (h *ArticleHandler) observeScore(fireScore *models.FIREScore) {
+	category := h.thresholds.For(fireScore.ModelVersion).Category(fireScore.OverallScore)
+	metrics.FIREScores.WithLabelValues(category, fireScore.ModelVersion).Observe(float64(fireScore.OverallScore))
+}
+
+// submitArticleAsync queues scoring and persistence for the article and responds with the job.
+// key is the submitting API key, if any, whose reserved article is released if the job fails.
+func (h *ArticleHandler) submitArticleAsync(w http.ResponseWriter, r *http.Request, key *models.PartnerKey, article models.Article) {
+	owner := ""
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		owner = principal.Subject
+	}
+	job, err := h.jobs.Enqueue(r.Context(), owner, func(ctx context.Context) (articleID string, fireScore *models.FIREScore, err error) {
+		if key != nil {
+			defer func() {
+				if err != nil {
+					h.partnerKeys.ReleaseArticles(ctx, key, 1)
+				}
+			}()
+		}
 
-	// Save article to Firestore
-	articleID, err := h.firestoreService.SaveArticle(&article)
+		fireScore, err = h.models.PredictFIREScore(ctx, article.PredictionInput())
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to calculate FIRE score: %w", err)
+		}
+		article.FIREScore = fireScore
+
+		articleID, err = h.store.SaveArticle(ctx, &article)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to save article: %w", err)
+		}
+		h.observeScore(fireScore)
+		h.shadow.Score(ctx, articleID, article.PredictionInput(), fireScore)
+		slog.InfoContext(ctx, "Article scored and saved", "article_id", articleID, "fire_score", fireScore.OverallScore)
+		return articleID, fireScore, nil
+	})
 	if err != nil {
-		log.Printf("Failed to save article to Firestore: %v", err)
-		http.Error(w, "Failed to save article", http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "Failed to queue article", "error", err)
+		if key != nil {
+			h.partnerKeys.ReleaseArticles(r.Context(), key, 1)
+		}
+		http.Error(w, "Submission queue is full, try again later", http.StatusServiceUnavailable)
 		return
 	}
 
-	log.Printf("Article saved to Firestore with ID: %s", articleID)
+	slog.InfoContext(r.Context(), "Queued article", "job_id", job.ID)
 
-	// Create response matching frontend expectations
-	response := map[string]interface{}{
-		"article_id": articleID,
-		"fire_score": map[string]interface{}{
-			"score":      fireScore.OverallScore,
-			"confidence": fireScore.Confidence,
-			"label":      getLabelFromScore(fireScore.OverallScore),
-			"category":   getCategoryFromScore(fireScore.OverallScore),
-		},
-	}
-
-	// Return result to frontend
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(h.thresholds.jobResponse(job))
+}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Failed to encode response: %v", err)
+// reserveArticles counts n articles against the key's daily quota, writing a 429 or 500 and returning false
+// if they can't be reserved
+func (h *ArticleHandler) reserveArticles(w http.ResponseWriter, r *http.Request, key *models.PartnerKey, n int) bool {
+	err := h.partnerKeys.ReserveArticles(r.Context(), key, n)
+	if err == nil {
+		return true
+	}
+	var quotaErr *services.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		slog.InfoContext(r.Context(), "Partner key over quota", "partner_key_id", key.ID, "error", err)
+		WriteQuotaExceeded(w, quotaErr)
+		return false
+	}
+	slog.ErrorContext(r.Context(), "Failed to reserve article quota", "partner_key_id", key.ID, "error", err)
+	http.Error(w, "Failed to check article quota", http.StatusInternalServerError)
+	return false
+}
+
+// ScoreThresholds are the FIRE score cut-offs between labels and categories
+type ScoreThresholds struct {
+	// Real is the lowest score labelled real and categorised "No risk detected"
+	Real int
+	// Unverified is the lowest score categorised "Unverified"; lower scores are "Likely misleading"
+	Unverified int
+}
+
+// VersionThresholds holds the thresholds of each model version, since a retrained model may need
+// different cut-offs; versions without their own use Default
+type VersionThresholds struct {
+	Default   ScoreThresholds
+	ByVersion map[string]ScoreThresholds
+}
+
+// For returns the thresholds for scores produced by version
+func (v VersionThresholds) For(version string) ScoreThresholds {
+	if t, ok := v.ByVersion[version]; ok {
+		return t
 	}
+	return v.Default
 }
 
-// Helper function to get label from score
+// Label returns the label for score
 // Higher score = more reliable (real), Lower score = less reliable (fake)
-func getLabelFromScore(score int) string {
-	if score >= 50 {
+func (t ScoreThresholds) Label(score int) string {
+	if score >= t.Real {
 		return "real"
 	}
 	return "fake"
 }
 
-// Helper function to get category from score
+// Category returns the category for score
 // Higher score = safer, Lower score = riskier
-func getCategoryFromScore(score int) string {
-	if score >= 50 {
+func (t ScoreThresholds) Category(score int) string {
+	if score >= t.Real {
 		return "No risk detected"
-	} else if score >= 35 {
+	} else if score >= t.Unverified {
 		return "Unverified"
 	}
 	return "Likely misleading"
 }
 
-func getConfidenceFromScore(score int) float64 {
-	if score >= 50 {
-		// True news: score = 50 + (confidence * 50)
-		return float64(score-50) / 50.0
+// Score places a moderator's label and confidence on the score scale, so the score gets the chosen label:
+// real runs from Real (no confidence) up to 100 and fake from just below Real down to 0
+func (t ScoreThresholds) Score(label string, confidence float64) int {
+	if label == "real" {
+		return min(t.Real+int(confidence*float64(100-t.Real)), 100)
+	}
+	top := max(t.Real-1, 0)
+	return top - int(confidence*float64(top))
+}
+
+// Confidence is the inverse of Score: how far score lies from Real towards 100 for real labels,
+// or towards 0 for fake ones
+func (t ScoreThresholds) Confidence(score int) float64 {
+	if score >= t.Real {
+		if t.Real >= 100 {
+			return 1
+		}
+		return float64(score-t.Real) / float64(100-t.Real)
+	}
+	if t.Real <= 1 {
+		return 1
 	}
-	// Fake news: score = 50 - (confidence * 50)
-	return float64(50-score) / 50.0
+	return float64(t.Real-1-score) / float64(t.Real-1)
 }
 
 // GetArticles handles GET /api/v1/articles
+// The body is one page of articles; when more remain, X-Next-Page-Token carries the page_token for the next request.
+// See articleQueryFromRequest for the supported filters.
 func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
-	// Retrieve articles from Firestore (limit to 50)
-	articles, err := h.firestoreService.GetArticles(50)
+	// Label and category filters use the active version's thresholds
+	query, err := articleQueryFromRequest(r, h.thresholds.For(h.models.Active().Spec.Version))
 	if err != nil {
-		log.Printf("Failed to retrieve articles from Firestore: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.store.GetArticles(r.Context(), query)
+	if errors.Is(err, services.ErrInvalidPageToken) {
+		http.Error(w, "Invalid page_token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve articles", "error", err)
 		http.Error(w, "Failed to retrieve articles", http.StatusInternalServerError)
 		return
 	}
+	articles := page.Articles
 
-	log.Printf("Retrieved %d articles from Firestore", len(articles))
+	slog.DebugContext(r.Context(), "Retrieved articles", "count", len(articles))
 
 	// Transform articles to include calculated fields (label, category, confidence)
 	response := make([]map[string]interface{}, 0, len(articles))
-	for i, article := range articles {
-		log.Printf("Article %d: ID=%s, Title=%s, Source=%s", i+1, article.ID, article.Title, article.Source)
-		log.Printf("PublishedAt=%v, HasFIREScore=%v", article.PublishedAt, article.FIREScore != nil)
-
+	for _, article := range articles {
 		articleMap := map[string]interface{}{
 			"id":            article.ID,
 			"title":         article.Title,
@@ -162,29 +377,31 @@ func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
 			"source":        article.Source,
 			"author":        article.Author,
 			"publishedAt":   article.PublishedAt,
+			"submittedAt":   article.SubmittedAt,
 			"model_version": article.ModelVersion,
+			"model_score":   article.ModelScore,
 		}
+		addSubScores(articleMap, article)
 
 		if article.FIREScore != nil {
-			log.Printf("   FIRE Score=%d, Confidence will be %.2f", article.FIREScore.OverallScore, getConfidenceFromScore(article.FIREScore.OverallScore))
+			thresholds := h.thresholds.For(article.ModelVersion)
 			articleMap["fire_score"] = map[string]interface{}{
 				"score":      article.FIREScore.OverallScore,
-				"confidence": getConfidenceFromScore(article.FIREScore.OverallScore),
-				"label":      getLabelFromScore(article.FIREScore.OverallScore),
-				"category":   getCategoryFromScore(article.FIREScore.OverallScore),
+				"confidence": thresholds.Confidence(article.FIREScore.OverallScore),
+				"label":      thresholds.Label(article.FIREScore.OverallScore),
+				"category":   thresholds.Category(article.FIREScore.OverallScore),
 			}
 		}
 
 		response = append(response, articleMap)
 	}
 
-	log.Printf("✅ Sending response with %d articles", len(response))
-
+	if page.NextPageToken != "" {
+		w.Header().Set("X-Next-Page-Token", page.NextPageToken)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Failed to encode response: %v", err)
-	} else {
-		log.Printf("Response sent successfully")
+		slog.ErrorContext(r.Context(), "Failed to encode response", "error", err)
 	}
 }
 
@@ -199,22 +416,38 @@ func (h *ArticleHandler) ReportArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse request body (optional reason field)
+	// Parse request body (reason and category are both optional)
 	var reqBody struct {
-		Reason string `json:"reason"`
+		Reason   string `json:"reason"`
+		Category string `json:"category"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
-		// Ignore parse errors - reason is optional
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	log.Printf("Reporting article %s for moderation. Reason: %s", articleID, reqBody.Reason)
+	report, err := reportFromRequest(r, articleID, reqBody.Category, reqBody.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Mark article as needing moderation in Firestore
-	if err := h.firestoreService.ReportArticle(articleID); err != nil {
-		log.Printf("Failed to report article: %v", err)
+	// Record the report and mark article as needing moderation
+	if err := h.store.ReportArticle(r.Context(), report); err != nil {
+		if errors.Is(err, services.ErrArticleNotFound) {
+			http.Error(w, "Article not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateReport) {
+			http.Error(w, "You have already reported this article", http.StatusConflict)
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to report article", "article_id", articleID, "error", err)
 		http.Error(w, "Failed to report article", http.StatusInternalServerError)
 		return
 	}
+	metrics.Reports.WithLabelValues(report.Category).Inc()
+	slog.InfoContext(r.Context(), "Article reported", "article_id", articleID, "category", report.Category)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
@@ -223,42 +456,63 @@ func (h *ArticleHandler) ReportArticle(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetModeratorQueue handles GET /api/v1/moderator/queue
+// Paginated like GetArticles with page_size and page_token; X-Next-Page-Token is set while more remain.
 func (h *ArticleHandler) GetModeratorQueue(w http.ResponseWriter, r *http.Request) {
-	// Retrieve articles needing moderation (limit to 50)
-	articles, err := h.firestoreService.GetModeratorQueue(50)
+	pageSize, err := pageSizeFromRequest(r)
 	if err != nil {
-		log.Printf("Failed to retrieve moderator queue: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve articles needing moderation
+	page, err := h.store.GetModeratorQueue(r.Context(), pageSize, r.URL.Query().Get("page_token"))
+	if errors.Is(err, services.ErrInvalidPageToken) {
+		http.Error(w, "Invalid page_token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve moderator queue", "error", err)
 		http.Error(w, "Failed to retrieve moderator queue", http.StatusInternalServerError)
 		return
 	}
+	articles := page.Articles
 
-	log.Printf("Retrieved %d articles in moderation queue", len(articles))
+	slog.DebugContext(r.Context(), "Retrieved moderation queue", "count", len(articles))
 
 	// Transform articles to include calculated fields
 	response := make([]map[string]interface{}, 0, len(articles))
 	for _, article := range articles {
 		articleMap := map[string]interface{}{
-			"id":          article.ID,
-			"title":       article.Title,
-			"content":     article.Content,
-			"url":         article.URL,
-			"source":      article.Source,
-			"author":      article.Author,
-			"publishedAt": article.PublishedAt,
+			"id":           article.ID,
+			"title":        article.Title,
+			"content":      article.Content,
+			"url":          article.URL,
+			"source":       article.Source,
+			"author":       article.Author,
+			"publishedAt":  article.PublishedAt,
+			"report_count": article.ReportCount,
+		}
+		addSubScores(articleMap, article)
+		if !article.LatestReportAt.IsZero() {
+			articleMap["latest_report_at"] = article.LatestReportAt
 		}
 
 		if article.FIREScore != nil {
+			thresholds := h.thresholds.For(article.ModelVersion)
 			articleMap["fire_score"] = map[string]interface{}{
 				"score":      article.FIREScore.OverallScore,
-				"confidence": getConfidenceFromScore(article.FIREScore.OverallScore),
-				"label":      getLabelFromScore(article.FIREScore.OverallScore),
-				"category":   getCategoryFromScore(article.FIREScore.OverallScore),
+				"confidence": thresholds.Confidence(article.FIREScore.OverallScore),
+				"label":      thresholds.Label(article.FIREScore.OverallScore),
+				"category":   thresholds.Category(article.FIREScore.OverallScore),
 			}
 		}
 
 		response = append(response, articleMap)
 	}
 
+	if page.NextPageToken != "" {
+		w.Header().Set("X-Next-Page-Token", page.NextPageToken)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -274,15 +528,17 @@ func (h *ArticleHandler) GetArticleByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	log.Printf("Fetching article with ID: %s", articleID)
-
-	// Retrieve article from Firestore
-	article, err := h.firestoreService.GetArticleByID(articleID)
-	if err != nil {
-		log.Printf("Failed to retrieve article: %v", err)
+	// Retrieve article from the store
+	article, err := h.store.GetArticleByID(r.Context(), articleID)
+	if errors.Is(err, services.ErrArticleNotFound) {
 		http.Error(w, "Article not found", http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve article", "article_id", articleID, "error", err)
+		http.Error(w, "Failed to retrieve article", http.StatusInternalServerError)
+		return
+	}
 
 	// Transform article to include calculated fields
 	response := map[string]interface{}{
@@ -294,14 +550,17 @@ func (h *ArticleHandler) GetArticleByID(w http.ResponseWriter, r *http.Request)
 		"author":        article.Author,
 		"publishedAt":   article.PublishedAt,
 		"model_version": article.ModelVersion,
+		"model_score":   article.ModelScore,
 	}
+	addSubScores(response, article)
 
 	if article.FIREScore != nil {
+		thresholds := h.thresholds.For(article.ModelVersion)
 		response["fire_score"] = map[string]interface{}{
 			"score":      article.FIREScore.OverallScore,
-			"confidence": getConfidenceFromScore(article.FIREScore.OverallScore),
-			"label":      getLabelFromScore(article.FIREScore.OverallScore),
-			"category":   getCategoryFromScore(article.FIREScore.OverallScore),
+			"confidence": thresholds.Confidence(article.FIREScore.OverallScore),
+			"label":      thresholds.Label(article.FIREScore.OverallScore),
+			"category":   thresholds.Category(article.FIREScore.OverallScore),
 		}
 	}
 
@@ -317,15 +576,25 @@ func (h *ArticleHandler) OverrideFIREScore(w http.ResponseWriter, r *http.Reques
 		NewLabel   string  `json:"new_label"`
 		Confidence float64 `json:"confidence"`
 		Notes      string  `json:"notes"`
+		// ModeratorID identifies who made the change in the override history.
+		// It is replaced by the signed-in user when the request carries a verified ID token.
+		ModeratorID string `json:"moderator_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		reqBody.ModeratorID = principal.Name()
+	}
 
-	if reqBody.ArticleID == "" || reqBody.NewLabel == "" {
-		http.Error(w, "article_id and new_label are required", http.StatusBadRequest)
+	if reqBody.ArticleID == "" || reqBody.NewLabel == "" || reqBody.ModeratorID == "" {
+		http.Error(w, "article_id, new_label and moderator_id are required", http.StatusBadRequest)
+		return
+	}
+	if reqBody.NewLabel != "real" && reqBody.NewLabel != "fake" {
+		http.Error(w, "new_label must be real or fake", http.StatusBadRequest)
 		return
 	}
 
@@ -340,42 +609,53 @@ func (h *ArticleHandler) OverrideFIREScore(w http.ResponseWriter, r *http.Reques
 		reqBody.Confidence = 0.8
 	}
 
-	log.Printf("Moderator override for article %s: new_label=%s, confidence=%.2f, has_notes=%v",
-		reqBody.ArticleID, reqBody.NewLabel, reqBody.Confidence, reqBody.Notes != "")
-
-	// Calculate new FIRE score based on moderator's label and confidence
-	// Using same logic as ML model:
-	// - If label is "real": score = 50 + (confidence * 50) = range 50-100
-	// - If label is "fake": score = 50 - (confidence * 50) = range 0-50
-	var newFIREScore int
-	if reqBody.NewLabel == "real" {
-		newFIREScore = int(50 + (reqBody.Confidence * 50))
-	} else {
-		newFIREScore = int(50 - (reqBody.Confidence * 50))
+	// The new score is placed around the real threshold of the version that scored the article,
+	// so the stored score carries the label the moderator chose
+	article, err := h.store.GetArticleByID(r.Context(), reqBody.ArticleID)
+	if errors.Is(err, services.ErrArticleNotFound) {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return
 	}
-
-	log.Printf("Calculated new FIRE score: %d (label=%s, confidence=%.2f)",
-		newFIREScore, reqBody.NewLabel, reqBody.Confidence)
-
-	// Save moderator note if provided
-	if reqBody.Notes != "" {
-		if err := h.firestoreService.SaveModeratorNote(reqBody.ArticleID, reqBody.Notes, reqBody.NewLabel); err != nil {
-			log.Printf("Failed to save moderator note: %v", err)
-			http.Error(w, "Failed to save moderator note", http.StatusInternalServerError)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve article", "article_id", reqBody.ArticleID, "error", err)
+		http.Error(w, "Failed to apply override", http.StatusInternalServerError)
+		return
+	}
+	newFIREScore := h.thresholds.For(article.ModelVersion).Score(reqBody.NewLabel, reqBody.Confidence)
+
+	// Apply the override: update fire_score, clear needs_moderation and append it to the article's history
+	override := &models.Override{
+		ArticleID:   reqBody.ArticleID,
+		NewScore:    newFIREScore,
+		NewLabel:    reqBody.NewLabel,
+		Confidence:  reqBody.Confidence,
+		ModeratorID: reqBody.ModeratorID,
+		Notes:       reqBody.Notes,
+		CreatedAt:   time.Now(),
+	}
+	if err := h.store.ApplyModeratorOverride(r.Context(), override); err != nil {
+		if errors.Is(err, services.ErrArticleNotFound) {
+			http.Error(w, "Article not found", http.StatusNotFound)
 			return
 		}
-	}
-
-	// Apply the override: update fire_score and clear needs_moderation
-	if err := h.firestoreService.ApplyModeratorOverride(reqBody.ArticleID, newFIREScore); err != nil {
-		log.Printf("Failed to apply moderator override: %v", err)
+		if errors.Is(err, services.ErrConcurrentUpdate) {
+			slog.WarnContext(r.Context(), "Moderator override conflicted with concurrent updates", "article_id", reqBody.ArticleID)
+			http.Error(w, "Article is being updated by someone else, try again", http.StatusConflict)
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to apply moderator override", "article_id", reqBody.ArticleID, "error", err)
 		http.Error(w, "Failed to apply override", http.StatusInternalServerError)
 		return
 	}
+	metrics.Overrides.WithLabelValues(override.NewLabel).Inc()
+	slog.InfoContext(r.Context(), "Moderator override applied", "article_id", override.ArticleID, "moderator", override.ModeratorID,
+		"new_label", override.NewLabel, "confidence", override.Confidence, "previous_score", override.PreviousScore, "new_score", newFIREScore)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message":        "Override saved successfully",
+		"override_id":    override.ID,
+		"previous_score": override.PreviousScore,
 		"new_fire_score": newFIREScore,
 	})
 }
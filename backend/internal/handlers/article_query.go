@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// scoreRange is an inclusive FIRE score interval
+type scoreRange struct{ min, max int }
+
+// labelScoreRanges and categoryScoreRanges mirror Label and Category,
+// so label and category filters can be answered with a fire_score range query
+func (t ScoreThresholds) labelScoreRanges() map[string]scoreRange {
+	return map[string]scoreRange{
+		"real": {t.Real, 100},
+		"fake": {0, t.Real - 1},
+	}
+}
+
+func (t ScoreThresholds) categoryScoreRanges() map[string]scoreRange {
+	return map[string]scoreRange{
+		"no risk detected":  {t.Real, 100},
+		"unverified":        {t.Unverified, t.Real - 1},
+		"likely misleading": {0, t.Unverified - 1},
+	}
+}
+
+// articleQueryFromRequest parses the list filters for GET /api/v1/articles:
+//
+//	page_size, page_token                      pagination (page_size defaults to 50, max 200)
+//	source, author, model_version              exact matches
+//	label (real|fake), category, min_score, max_score   FIRE score filters
+//	published_from, published_to               RFC3339 or YYYY-MM-DD; from is inclusive, to is exclusive
+//	submitted_from, submitted_to               (a date-only "to" includes that whole day)
+//	sort (submitted_at|published_at|fire_score), order (asc|desc, default desc)
+func articleQueryFromRequest(r *http.Request, thresholds ScoreThresholds) (models.ArticleQuery, error) {
+	params := r.URL.Query()
+	query := models.ArticleQuery{
+		PageSize:     defaultPageSize,
+		PageToken:    params.Get("page_token"),
+		Source:       params.Get("source"),
+		Author:       params.Get("author"),
+		ModelVersion: params.Get("model_version"),
+		Descending:   true,
+	}
+
+	pageSize, err := pageSizeFromRequest(r)
+	if err != nil {
+		return query, err
+	}
+	query.PageSize = pageSize
+
+	scores := scoreRange{0, 100}
+	if v := params.Get("min_score"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return query, fmt.Errorf("min_score must be an integer")
+		}
+		scores.min = max(scores.min, n)
+	}
+	if v := params.Get("max_score"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return query, fmt.Errorf("max_score must be an integer")
+		}
+		scores.max = min(scores.max, n)
+	}
+	if v := params.Get("label"); v != "" {
+		r, ok := thresholds.labelScoreRanges()[strings.ToLower(v)]
+		if !ok {
+			return query, fmt.Errorf("label must be real or fake")
+		}
+		scores.min, scores.max = max(scores.min, r.min), min(scores.max, r.max)
+	}
+	if v := params.Get("category"); v != "" {
+		r, ok := thresholds.categoryScoreRanges()[strings.ToLower(v)]
+		if !ok {
+			return query, fmt.Errorf("category must be one of: No risk detected, Unverified, Likely misleading")
+		}
+		scores.min, scores.max = max(scores.min, r.min), min(scores.max, r.max)
+	}
+	if scores.min > 0 {
+		query.MinScore = &scores.min
+	}
+	if scores.max < 100 {
+		query.MaxScore = &scores.max
+	}
+
+	if query.PublishedFrom, err = parseTimeParam(params.Get("published_from"), false); err != nil {
+		return query, fmt.Errorf("published_from: %w", err)
+	}
+	if query.PublishedTo, err = parseTimeParam(params.Get("published_to"), true); err != nil {
+		return query, fmt.Errorf("published_to: %w", err)
+	}
+	if query.SubmittedFrom, err = parseTimeParam(params.Get("submitted_from"), false); err != nil {
+		return query, fmt.Errorf("submitted_from: %w", err)
+	}
+	if query.SubmittedTo, err = parseTimeParam(params.Get("submitted_to"), true); err != nil {
+		return query, fmt.Errorf("submitted_to: %w", err)
+	}
+
+	switch sort := params.Get("sort"); sort {
+	case "", models.SortBySubmittedAt:
+		query.SortBy = models.SortBySubmittedAt
+	case models.SortByPublishedAt, models.SortByFIREScore:
+		query.SortBy = sort
+	default:
+		return query, fmt.Errorf("sort must be submitted_at, published_at or fire_score")
+	}
+	switch params.Get("order") {
+	case "", "desc":
+	case "asc":
+		query.Descending = false
+	default:
+		return query, fmt.Errorf("order must be asc or desc")
+	}
+
+	return query, nil
+}
+
+// pageSizeFromRequest parses page_size, defaulting to 50 and capping at 200
+func pageSizeFromRequest(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("page_size")
+	if v == "" {
+		return defaultPageSize, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("page_size must be a positive integer")
+	}
+	return min(n, maxPageSize), nil
+}
+
+// parseTimeParam accepts RFC3339 or YYYY-MM-DD. For an exclusive upper bound a bare date
+// is moved to the following midnight so the named day is included.
+func parseTimeParam(v string, upperBound bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sync"
+
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// maxBatchSize caps how many articles one batch request may contain
+const maxBatchSize = 100
+
+// maxBatchBodyBytes caps the size of a batch request body, so an oversized batch is rejected while it is
+// being read rather than after all of it has been decoded
+const maxBatchBodyBytes = 10 << 20
+
+// errBatchTooLarge is returned by decodeBatch when the body exceeds maxBatchBodyBytes
+var errBatchTooLarge = fmt.Errorf("Batch exceeds %d bytes", maxBatchBodyBytes)
+
+// batchItemResult is the per-article outcome returned by SubmitArticleBatch
+type batchItemResult struct {
+	Index     int                    `json:"index"`
+	ArticleID string                 `json:"article_id,omitempty"`
+	FIREScore map[string]interface{} `json:"fire_score,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// SubmitArticleBatch handles POST /api/v1/partner/submit/batch
+// The body is a JSON array of submissions, or one submission per line with Content-Type application/x-ndjson.
+// Every item is validated, scored and saved independently; failures are reported per item
+// and never abort the rest of the batch.
+func (h *ArticleHandler) SubmitArticleBatch(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)
+	requests, err := decodeBatch(r)
+	if errors.Is(err, errBatchTooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		slog.InfoContext(r.Context(), "Failed to decode batch", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(requests) == 0 {
+		http.Error(w, "Batch is empty", http.StatusBadRequest)
+		return
+	}
+	if len(requests) > maxBatchSize {
+		http.Error(w, fmt.Sprintf("Batch exceeds %d articles", maxBatchSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	key := requestPartnerKey(r)
+	results := make([]batchItemResult, len(requests))
+	articles := make([]*models.Article, len(requests))
+	accepted := 0
+	for i := range requests {
+		results[i].Index = i
+		article, err := articleFromRequest(&requests[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if key != nil {
+			if !services.SourceAllowed(key, article.Source) {
+				results[i].Error = fmt.Sprintf("Source %q is not allowed for this API key", article.Source)
+				continue
+			}
+			article.PartnerKeyID = key.ID
+		}
+		articles[i] = &article
+		accepted++
+	}
+
+	// The whole batch is rejected if the key's remaining article quota can't cover every valid item
+	if key != nil && accepted > 0 && !h.reserveArticles(w, r, key, accepted) {
+		return
+	}
+
+	// Scoring has to stop in time to save the results and respond before the server's write timeout;
+	// items that haven't been scored by then fail rather than the whole response being lost
+	scoreCtx := r.Context()
+	if h.batchTimeout > 0 {
+		var cancel context.CancelFunc
+		scoreCtx, cancel = context.WithTimeout(scoreCtx, h.batchTimeout*9/10)
+		defer cancel()
+	}
+
+	// Score in parallel, but no faster than the ML pool can serve so queued items don't eat into their timeout
+	slog.DebugContext(r.Context(), "Predicting FIRE scores for batch", "articles", len(requests))
+	sem := make(chan struct{}, h.models.Active().ML.Workers())
+	var wg sync.WaitGroup
+	for i, article := range articles {
+		if article == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, article *models.Article) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-scoreCtx.Done():
+				results[i].Error = "Batch deadline reached before this article was scored"
+				articles[i] = nil
+				return
+			}
+			defer func() { <-sem }()
+
+			fireScore, err := h.models.PredictFIREScore(scoreCtx, article.PredictionInput())
+			if err != nil {
+				slog.ErrorContext(r.Context(), "ML prediction failed for batch item", "index", i, "error", err)
+				results[i].Error = "Failed to calculate FIRE score"
+				var timeoutErr *services.PredictionTimeoutError
+				if errors.As(err, &timeoutErr) {
+					results[i].Error = "Timed out calculating FIRE score"
+				}
+				if errors.Is(scoreCtx.Err(), context.DeadlineExceeded) {
+					results[i].Error = "Batch deadline reached before this article was scored"
+				}
+				articles[i] = nil
+				return
+			}
+			article.FIREScore = fireScore
+		}(i, article)
+	}
+	wg.Wait()
+
+	// Save everything that scored in one store batch
+	var toSave []*models.Article
+	var saveIndexes []int
+	for i, article := range articles {
+		if article != nil {
+			toSave = append(toSave, article)
+			saveIndexes = append(saveIndexes, i)
+		}
+	}
+	if len(toSave) > 0 {
+		for j, saved := range h.store.SaveArticles(r.Context(), toSave) {
+			i := saveIndexes[j]
+			if saved.Err != nil {
+				slog.ErrorContext(r.Context(), "Failed to save batch item", "index", i, "error", saved.Err)
+				results[i].Error = "Failed to save article"
+				continue
+			}
+			results[i].ArticleID = saved.ID
+			h.observeScore(toSave[j].FIREScore)
+			h.shadow.Score(r.Context(), saved.ID, toSave[j].PredictionInput(), toSave[j].FIREScore)
+			results[i].FIREScore = h.thresholds.fireScoreResponse(toSave[j].FIREScore)
+		}
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Error == "" {
+			succeeded++
+		}
+	}
+	if key != nil {
+		h.partnerKeys.ReleaseArticles(r.Context(), key, accepted-succeeded)
+	}
+	slog.InfoContext(r.Context(), "Batch complete", "succeeded", succeeded, "failed", len(results)-succeeded)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+// decodeBatch reads the submissions from a JSON array or an NDJSON body
+func decodeBatch(r *http.Request) ([]models.CreateArticleRequest, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-ndjson" && mediaType != "application/ndjson" {
+		var requests []models.CreateArticleRequest
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+				return nil, errBatchTooLarge
+			}
+			return nil, errors.New("Invalid request body: expected a JSON array of articles")
+		}
+		return requests, nil
+	}
+
+	var requests []models.CreateArticleRequest
+	decoder := json.NewDecoder(r.Body)
+	for {
+		var req models.CreateArticleRequest
+		err := decoder.Decode(&req)
+		if err == io.EOF {
+			return requests, nil
+		}
+		if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+			return nil, errBatchTooLarge
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid NDJSON on item %d", len(requests))
+		}
+		requests = append(requests, req)
+		if len(requests) > maxBatchSize {
+			return requests, nil
+		}
+	}
+}
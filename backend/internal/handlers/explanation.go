@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// errModelNotLoaded is returned when the model version that scored an article isn't in the registry
+var errModelNotLoaded = errors.New("the model version that scored the article is not loaded")
+
+// GetArticleExplanation handles GET /api/v1/articles/{id}/explanation
+// Returns the words that contributed most to the model's score. The explanation is computed on the first request
+// and stored with the article; concurrent first requests share one computation.
+func (h *ArticleHandler) GetArticleExplanation(w http.ResponseWriter, r *http.Request) {
+	articleID := mux.Vars(r)["id"]
+
+	explanation, err := h.store.GetExplanation(r.Context(), articleID)
+	if errors.Is(err, services.ErrArticleNotFound) {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve explanation", "article_id", articleID, "error", err)
+		http.Error(w, "Failed to retrieve explanation", http.StatusInternalServerError)
+		return
+	}
+
+	if explanation == nil {
+		// The computation outlives a client that gives up, so its result is still stored for the next request
+		ctx := context.WithoutCancel(r.Context())
+		result, err, _ := h.explanations.Do(articleID, func() (interface{}, error) {
+			return h.computeExplanation(ctx, articleID)
+		})
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to explain FIRE score", "article_id", articleID, "error", err)
+			var timeoutErr *services.PredictionTimeoutError
+			switch {
+			case errors.Is(err, services.ErrArticleNotFound):
+				http.Error(w, "Article not found", http.StatusNotFound)
+			case errors.Is(err, errModelNotLoaded):
+				http.Error(w, "The model version that scored this article is not loaded", http.StatusConflict)
+			case errors.As(err, &timeoutErr):
+				http.Error(w, "Timed out explaining FIRE score", http.StatusGatewayTimeout)
+			default:
+				http.Error(w, "Failed to explain FIRE score", http.StatusInternalServerError)
+			}
+			return
+		}
+		explanation = result.(*models.Explanation)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"article_id":    articleID,
+		"method":        explanation.Method,
+		"model_version": explanation.ModelVersion,
+		"attributions":  explanation.Attributions,
+		"truncated":     explanation.Truncated,
+		"computed_at":   explanation.ComputedAt,
+	})
+}
+
+// computeExplanation explains the article's score with the model version that produced it and stores the result
+func (h *ArticleHandler) computeExplanation(ctx context.Context, articleID string) (*models.Explanation, error) {
+	article, err := h.store.GetArticleByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	model, ok := h.models.Get(article.ModelVersion)
+	if !ok {
+		return nil, errModelNotLoaded
+	}
+	explanation, err := model.ML.Explain(ctx, article.PredictionInput())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.store.SaveExplanation(ctx, articleID, explanation); err != nil {
+		// The explanation is still good; it will just be computed again next time
+		slog.ErrorContext(ctx, "Failed to store explanation", "article_id", articleID, "error", err)
+	}
+	return explanation, nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"backend/internal/services"
+)
+
+// HealthCheck is one dependency verified by the readiness probe
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	checks  []HealthCheck
+	timeout time.Duration
+	models  *services.ModelRegistry
+}
+
+// NewHealthHandler creates a handler that runs checks, each limited to timeout, on every readiness probe
+// and reports the active version of models
+func NewHealthHandler(timeout time.Duration, models *services.ModelRegistry, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks, timeout: timeout, models: models}
+}
+
+// GetLiveness handles GET /health/live (and /health): the process is up and serving requests
+func (h *HealthHandler) GetLiveness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "alive",
+	})
+}
+
+// GetReadiness handles GET /health/ready
+// Every check runs concurrently; the response is 200 if all pass and 503 otherwise,
+// with each dependency's status, latency and error.
+func (h *HealthHandler) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	results := make(map[string]interface{}, len(h.checks))
+	ready := true
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(ctx)
+			result := map[string]interface{}{
+				"status":     "ok",
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result["status"] = "error"
+				result["error"] = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[check.Name] = result
+			if err != nil {
+				ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":        status,
+		"model_version": h.models.Active().Spec.Version,
+		"checks":        results,
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/services"
+)
+
+// GetArticleHistory handles GET /api/v1/articles/{id}/history
+// Returns the model's original score, the current effective score and every moderator override, oldest first.
+func (h *ArticleHandler) GetArticleHistory(w http.ResponseWriter, r *http.Request) {
+	articleID := mux.Vars(r)["id"]
+
+	article, err := h.store.GetArticleByID(r.Context(), articleID)
+	if errors.Is(err, services.ErrArticleNotFound) {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve article", "article_id", articleID, "error", err)
+		http.Error(w, "Failed to retrieve article history", http.StatusInternalServerError)
+		return
+	}
+
+	overrides, err := h.store.GetOverrideHistory(r.Context(), articleID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve override history", "article_id", articleID, "error", err)
+		http.Error(w, "Failed to retrieve article history", http.StatusInternalServerError)
+		return
+	}
+
+	effectiveScore := article.ModelScore
+	if article.FIREScore != nil {
+		effectiveScore = article.FIREScore.OverallScore
+	}
+
+	response := map[string]interface{}{
+		"article_id":      article.ID,
+		"model_version":   article.ModelVersion,
+		"model_score":     article.ModelScore,
+		"effective_score": effectiveScore,
+		"overrides":       overrides,
+	}
+	addSubScores(response, article)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
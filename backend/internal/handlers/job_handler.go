@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/auth"
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// JobHandler serves the status of asynchronous submissions
+type JobHandler struct {
+	jobs       *services.JobQueue
+	thresholds VersionThresholds
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobs *services.JobQueue, thresholds VersionThresholds) *JobHandler {
+	return &JobHandler{jobs: jobs, thresholds: thresholds}
+}
+
+// GetJob handles GET /api/v1/jobs/{id}
+// Only the user or API key that submitted the job, or an admin, can see it; anyone else gets 404.
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	jobID := mux.Vars(r)["id"]
+
+	job, ok := h.jobs.Get(jobID)
+	if !ok || !canSeeJob(r, job) {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.thresholds.jobResponse(job))
+}
+
+// canSeeJob reports whether the caller submitted job or is an admin
+func canSeeJob(r *http.Request, job *models.Job) bool {
+	if job.Owner == "" {
+		return true
+	}
+	principal, ok := auth.FromContext(r.Context())
+	return ok && (principal.Subject == job.Owner || principal.IsAdmin())
+}
+
+// jobResponse formats a job the same way SubmitArticle formats a synchronous result
+func (t VersionThresholds) jobResponse(job *models.Job) map[string]interface{} {
+	response := map[string]interface{}{
+		"job_id":     job.ID,
+		"status":     job.Status,
+		"created_at": job.CreatedAt,
+		"updated_at": job.UpdatedAt,
+	}
+	if job.ArticleID != "" {
+		response["article_id"] = job.ArticleID
+	}
+	if job.FIREScore != nil {
+		response["fire_score"] = t.fireScoreResponse(job.FIREScore)
+	}
+	if job.Error != "" {
+		response["error"] = job.Error
+	}
+	return response
+}
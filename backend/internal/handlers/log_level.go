@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"backend/internal/logging"
+)
+
+// GetLogLevel handles GET /api/v1/admin/log-level
+func GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"level": logging.LevelName()})
+}
+
+// SetLogLevel handles PUT /api/v1/admin/log-level with {"level": "debug"}.
+// The change lasts until the process restarts; LOG_LEVEL sets the level it starts with.
+func SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var reqBody struct {
+		Level string `json:"level"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	previous := logging.LevelName()
+	if err := logging.SetLevel(reqBody.Level); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slog.WarnContext(r.Context(), "Log level changed", "from", previous, "to", logging.LevelName())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"level": logging.LevelName()})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"backend/internal/services"
+)
+
+// ModelHandler lets admins see the loaded model versions and choose which one scores new articles
+type ModelHandler struct {
+	models     *services.ModelRegistry
+	store      services.ShadowScoreStore
+	thresholds VersionThresholds
+}
+
+// NewModelHandler creates a new model handler
+func NewModelHandler(models *services.ModelRegistry, store services.ShadowScoreStore, thresholds VersionThresholds) *ModelHandler {
+	return &ModelHandler{models: models, store: store, thresholds: thresholds}
+}
+
+// ListModels handles GET /api/v1/admin/models
+// Lists every loaded version in manifest order with the thresholds its scores are labelled with
+// and whether it is active or scoring in shadow.
+func (h *ModelHandler) ListModels(w http.ResponseWriter, r *http.Request) {
+	active := h.models.Active().Spec.Version
+	shadow := ""
+	if m := h.models.Shadow(); m != nil {
+		shadow = m.Spec.Version
+	}
+	response := make([]map[string]interface{}, 0)
+	for _, m := range h.models.List() {
+		thresholds := h.thresholds.For(m.Spec.Version)
+		response = append(response, map[string]interface{}{
+			"version":    m.Spec.Version,
+			"checkpoint": m.Spec.Checkpoint,
+			"sha256":     m.Spec.SHA256,
+			"base_model": m.Spec.BaseModel,
+			"max_length": m.Spec.MaxLength,
+			"thresholds": map[string]int{"real": thresholds.Real, "unverified": thresholds.Unverified},
+			"workers":    m.ML.Workers(),
+			"active":     m.Spec.Version == active,
+			"shadow":     m.Spec.Version == shadow,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ActivateModel handles PUT /api/v1/admin/models/active with {"version": "v1.1.0"}.
+// The version must pass its checksum and a canary prediction first. The change lasts until the process
+// restarts; the manifest's active field sets the version it starts with.
+func (h *ModelHandler) ActivateModel(w http.ResponseWriter, r *http.Request) {
+	var reqBody struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.models.Activate(r.Context(), reqBody.Version)
+	if errors.Is(err, services.ErrModelVersionNotFound) {
+		http.Error(w, "Model version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.WarnContext(r.Context(), "Model version failed activation checks", "model_version", reqBody.Version, "error", err)
+		http.Error(w, "Model version failed activation checks: "+err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"active": reqBody.Version})
+}
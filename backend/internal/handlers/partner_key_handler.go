@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/auth"
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// PartnerKeyHandler handles partner API key administration and usage requests
+type PartnerKeyHandler struct {
+	keys *services.PartnerKeyService
+}
+
+// NewPartnerKeyHandler creates a new partner key handler
+func NewPartnerKeyHandler(keys *services.PartnerKeyService) *PartnerKeyHandler {
+	return &PartnerKeyHandler{keys: keys}
+}
+
+// partnerKeyRequest is the body of POST /api/v1/admin/partner-keys
+type partnerKeyRequest struct {
+	PartnerName       string   `json:"partner_name"`
+	AllowedSources    []string `json:"allowed_sources"`
+	DailyRequestQuota int      `json:"daily_request_quota"`
+	DailyArticleQuota int      `json:"daily_article_quota"`
+}
+
+// CreateKey handles POST /api/v1/admin/partner-keys
+// The response holds the full API key; it is not stored and can't be retrieved again.
+func (h *PartnerKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req partnerKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.PartnerName = strings.TrimSpace(req.PartnerName)
+	if req.PartnerName == "" {
+		http.Error(w, "partner_name is required", http.StatusBadRequest)
+		return
+	}
+	if req.DailyRequestQuota < 0 || req.DailyArticleQuota < 0 {
+		http.Error(w, "Quotas must be zero (unlimited) or positive", http.StatusBadRequest)
+		return
+	}
+
+	key := &models.PartnerKey{
+		PartnerName:       req.PartnerName,
+		AllowedSources:    cleanSources(req.AllowedSources),
+		DailyRequestQuota: req.DailyRequestQuota,
+		DailyArticleQuota: req.DailyArticleQuota,
+	}
+	apiKey, err := h.keys.Create(r.Context(), key)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to create partner key", "error", err)
+		http.Error(w, "Failed to create partner key", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_key":     apiKey,
+		"partner_key": key,
+	})
+}
+
+// cleanSources trims the listed sources and drops empty ones
+func cleanSources(list []string) []string {
+	sources := []string{}
+	for _, source := range list {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// ListKeys handles GET /api/v1/admin/partner-keys
+func (h *PartnerKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keys.List(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list partner keys", "error", err)
+		http.Error(w, "Failed to list partner keys", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// RotateKey handles POST /api/v1/admin/partner-keys/{id}/rotate
+// The previous API key stops working immediately.
+func (h *PartnerKeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	key, apiKey, err := h.keys.Rotate(r.Context(), mux.Vars(r)["id"])
+	switch {
+	case errors.Is(err, services.ErrPartnerKeyNotFound):
+		http.Error(w, "Partner key not found", http.StatusNotFound)
+		return
+	case errors.Is(err, services.ErrInvalidAPIKey):
+		http.Error(w, "Partner key has been revoked", http.StatusConflict)
+		return
+	case err != nil:
+		slog.ErrorContext(r.Context(), "Failed to rotate partner key", "error", err)
+		http.Error(w, "Failed to rotate partner key", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_key":     apiKey,
+		"partner_key": key,
+	})
+}
+
+// SetSources handles PUT /api/v1/admin/partner-keys/{id}/sources
+// Replaces the sources the key may submit articles from ({"allowed_sources": [...]}; empty allows any source).
+func (h *PartnerKeyHandler) SetSources(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AllowedSources []string `json:"allowed_sources"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.keys.SetAllowedSources(r.Context(), mux.Vars(r)["id"], cleanSources(req.AllowedSources))
+	switch {
+	case errors.Is(err, services.ErrPartnerKeyNotFound):
+		http.Error(w, "Partner key not found", http.StatusNotFound)
+		return
+	case errors.Is(err, services.ErrInvalidAPIKey):
+		http.Error(w, "Partner key has been revoked", http.StatusConflict)
+		return
+	case err != nil:
+		slog.ErrorContext(r.Context(), "Failed to change partner key sources", "error", err)
+		http.Error(w, "Failed to change partner key sources", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
+
+// RevokeKey handles DELETE /api/v1/admin/partner-keys/{id}
+// The key record is kept so articles it submitted stay attributable.
+func (h *PartnerKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.keys.Revoke(r.Context(), mux.Vars(r)["id"])
+	if errors.Is(err, services.ErrPartnerKeyNotFound) {
+		http.Error(w, "Partner key not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to revoke partner key", "error", err)
+		http.Error(w, "Failed to revoke partner key", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
+
+// GetUsage handles GET /api/v1/partner/usage
+// Returns today's counters and quotas for the API key the request was made with.
+func (h *PartnerKeyHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	key := requestPartnerKey(r)
+	if key == nil {
+		http.Error(w, "Usage is only tracked for requests made with an API key", http.StatusBadRequest)
+		return
+	}
+
+	usage, err := h.keys.Usage(r.Context(), key)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve partner key usage", "partner_key_id", key.ID, "error", err)
+		http.Error(w, "Failed to retrieve usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"partner_key_id":      key.ID,
+		"partner_name":        key.PartnerName,
+		"date":                usage.Day,
+		"requests":            usage.Requests,
+		"articles":            usage.Articles,
+		"daily_request_quota": key.DailyRequestQuota,
+		"daily_article_quota": key.DailyArticleQuota,
+	})
+}
+
+// requestPartnerKey returns the API key the request was authenticated with, or nil
+func requestPartnerKey(r *http.Request) *models.PartnerKey {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return principal.PartnerKey
+	}
+	return nil
+}
+
+// WriteQuotaExceeded sends a 429 whose Retry-After points at the quota's reset
+func WriteQuotaExceeded(w http.ResponseWriter, err *services.QuotaExceededError) {
+	retryAfter := int(math.Ceil(time.Until(err.ResetAt).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":    "quota_exceeded",
+		"quota":    err.Quota,
+		"limit":    err.Limit,
+		"reset_at": err.ResetAt,
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"backend/internal/auth"
+	"backend/internal/models"
+	"backend/internal/services"
+)
+
+// maxReportReasonLength caps the free-text reason stored with a report
+const maxReportReasonLength = 1000
+
+var reportCategories = map[string]bool{
+	models.ReportIncorrectScore: true,
+	models.ReportMisleading:     true,
+	models.ReportSatire:         true,
+	models.ReportSpam:           true,
+	models.ReportOther:          true,
+}
+
+// reportFromRequest validates the report fields and identifies the reporter
+func reportFromRequest(r *http.Request, articleID, category, reason string) (*models.Report, error) {
+	if category == "" {
+		category = models.ReportIncorrectScore
+	}
+	if !reportCategories[category] {
+		return nil, fmt.Errorf("Invalid category %q", category)
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxReportReasonLength {
+		return nil, fmt.Errorf("Reason exceeds %d characters", maxReportReasonLength)
+	}
+
+	return &models.Report{
+		ArticleID:  articleID,
+		Category:   category,
+		Reason:     reason,
+		ReporterID: reporterID(r),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// reporterID identifies the reporter for duplicate suppression: a signed-in user by UID, a partner by API key ID,
+// and anyone else by fingerprint
+func reporterID(r *http.Request) string {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return reporterFingerprint(r)
+	}
+	if principal.PartnerKey != nil {
+		return "partner_key:" + principal.PartnerKey.ID
+	}
+	return "user:" + principal.Subject
+}
+
+// reporterFingerprint identifies an anonymous reporter by a hash of their client address and user agent
+func reporterFingerprint(r *http.Request) string {
+	sum := sha256.Sum256([]byte(ClientIP(r) + "|" + r.UserAgent()))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetArticleReports handles GET /api/v1/moderator/articles/{id}/reports
+func (h *ArticleHandler) GetArticleReports(w http.ResponseWriter, r *http.Request) {
+	articleID := mux.Vars(r)["id"]
+
+	reports, err := h.store.GetReports(r.Context(), articleID)
+	if errors.Is(err, services.ErrArticleNotFound) {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to retrieve reports", "article_id", articleID, "error", err)
+		http.Error(w, "Failed to retrieve reports", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
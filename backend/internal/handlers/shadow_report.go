@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"backend/internal/models"
+)
+
+// scoreCategories are the FIRE score categories from most to least reliable
+var scoreCategories = []string{"No risk detected", "Unverified", "Likely misleading"}
+
+// shadowReport compares a candidate version's shadow scores with the active model's scores of the same articles
+type shadowReport struct {
+	ModelVersion string `json:"model_version"`
+	// Running is whether the version is still scoring new articles in shadow
+	Running  bool `json:"running"`
+	Compared int  `json:"compared"`
+	// AgreementRate is the share of articles put in the same category by both models and LabelAgreementRate
+	// the share given the same label; they and ScoreDelta are null until an article has been compared
+	AgreementRate      *float64           `json:"agreement_rate"`
+	LabelAgreementRate *float64           `json:"label_agreement_rate"`
+	ScoreDelta         *scoreDeltaSummary `json:"score_delta"`
+	// Categories break the comparison down by the category the active model chose
+	Categories []categoryAgreement `json:"categories"`
+}
+
+// scoreDeltaSummary describes candidate score minus active score across the compared articles
+type scoreDeltaSummary struct {
+	Mean         float64 `json:"mean"`
+	MeanAbsolute float64 `json:"mean_absolute"`
+	P50Absolute  int     `json:"p50_absolute"`
+	P90Absolute  int     `json:"p90_absolute"`
+	MaxAbsolute  int     `json:"max_absolute"`
+}
+
+// categoryAgreement counts the articles the active model put in Category and, for those the candidate
+// categorised differently, which category it chose instead
+type categoryAgreement struct {
+	Category      string         `json:"category"`
+	Compared      int            `json:"compared"`
+	Agreed        int            `json:"agreed"`
+	Disagreements map[string]int `json:"disagreements"`
+}
+
+// newShadowReport summarises scores, labelling each side with the thresholds of the version that produced it
+func newShadowReport(version string, scores []*models.ShadowScore, thresholds VersionThresholds) *shadowReport {
+	report := &shadowReport{ModelVersion: version, Compared: len(scores), Categories: []categoryAgreement{}}
+	byCategory := make(map[string]*categoryAgreement, len(scoreCategories))
+	for _, category := range scoreCategories {
+		byCategory[category] = &categoryAgreement{Category: category, Disagreements: map[string]int{}}
+	}
+
+	agreed, labelsAgreed, deltaSum, absoluteSum := 0, 0, 0, 0
+	absolute := make([]int, 0, len(scores))
+	for _, score := range scores {
+		active, candidate := thresholds.For(score.ActiveVersion), thresholds.For(score.ModelVersion)
+		activeCategory, candidateCategory := active.Category(score.ActiveScore), candidate.Category(score.Score)
+
+		c := byCategory[activeCategory]
+		c.Compared++
+		if activeCategory == candidateCategory {
+			c.Agreed++
+			agreed++
+		} else {
+			c.Disagreements[candidateCategory]++
+		}
+		if active.Label(score.ActiveScore) == candidate.Label(score.Score) {
+			labelsAgreed++
+		}
+
+		delta := score.Score - score.ActiveScore
+		deltaSum += delta
+		absoluteSum += max(delta, -delta)
+		absolute = append(absolute, max(delta, -delta))
+	}
+	for _, category := range scoreCategories {
+		report.Categories = append(report.Categories, *byCategory[category])
+	}
+	if len(scores) == 0 {
+		return report
+	}
+
+	n := float64(len(scores))
+	agreementRate, labelAgreementRate := float64(agreed)/n, float64(labelsAgreed)/n
+	report.AgreementRate, report.LabelAgreementRate = &agreementRate, &labelAgreementRate
+
+	sort.Ints(absolute)
+	report.ScoreDelta = &scoreDeltaSummary{
+		Mean:         float64(deltaSum) / n,
+		MeanAbsolute: float64(absoluteSum) / n,
+		P50Absolute:  percentile(absolute, 50),
+		P90Absolute:  percentile(absolute, 90),
+		MaxAbsolute:  absolute[len(absolute)-1],
+	}
+	return report
+}
+
+// percentile returns the nearest-rank pth percentile of sorted, which must not be empty
+func percentile(sorted []int, p int) int {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// ShadowReport handles GET /api/v1/admin/models/shadow
+// Compares the shadow version's scores, or those of ?version=, with the active model's scores of the same
+// articles. ?since= (RFC3339 or YYYY-MM-DD) limits the report to articles scored from then on.
+func (h *ModelHandler) ShadowReport(w http.ResponseWriter, r *http.Request) {
+	shadow := h.models.Shadow()
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		if shadow == nil {
+			http.Error(w, "No model version is running in shadow", http.StatusNotFound)
+			return
+		}
+		version = shadow.Spec.Version
+	}
+	since, err := parseTimeParam(r.URL.Query().Get("since"), false)
+	if err != nil {
+		http.Error(w, "since: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scores, err := h.store.GetShadowScores(r.Context(), version, since)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get shadow scores", "model_version", version, "error", err)
+		http.Error(w, "Failed to get shadow scores", http.StatusInternalServerError)
+		return
+	}
+
+	report := newShadowReport(version, scores, h.thresholds)
+	report.Running = shadow != nil && shadow.Spec.Version == version
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Every collector is registered with the default registry, which also exports Go runtime and process metrics
+
+var (
+	// HTTPRequests counts requests by route template (not raw path, to bound cardinality), method and status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fire_http_requests_total",
+		Help: "HTTP requests handled, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fire_http_request_duration_seconds",
+		Help:    "Time to handle HTTP requests, by route, method and status code.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"route", "method", "status"})
+
+	// MLPredictionDuration covers waiting for an idle worker as well as inference
+	MLPredictionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fire_ml_prediction_duration_seconds",
+		Help:    "Time to score one text, including waiting for a worker, by outcome.",
+		Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"outcome"})
+
+	// MLPredictionFailures counts failed predictions by reason: timeout, cancelled, model_error or worker_error
+	MLPredictionFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fire_ml_prediction_failures_total",
+		Help: "Failed ML predictions, by reason.",
+	}, []string{"reason"})
+
+	// MLExplanationDuration covers computing an article's explanation on a worker, which takes far longer than scoring
+	MLExplanationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fire_ml_explanation_duration_seconds",
+		Help:    "Time to explain one article's score, including waiting for a worker, by outcome.",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"outcome"})
+
+	// ShadowPredictions counts candidate predictions made in shadow by outcome: stored, failed, or skipped
+	// because too many were already pending
+	ShadowPredictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fire_shadow_predictions_total",
+		Help: "Shadow predictions by a candidate model version, by version and outcome.",
+	}, []string{"model_version", "outcome"})
+
+	StoreOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fire_store_operation_duration_seconds",
+		Help:    "Latency of article store calls, by backend and operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"backend", "operation"})
+
+	// StoreErrors excludes expected outcomes such as not found and duplicate reports
+	StoreErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fire_store_errors_total",
+		Help: "Failed article store calls, by backend and operation.",
+	}, []string{"backend", "operation"})
+
+	Reports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fire_reports_total",
+		Help: "Article reports accepted, by category.",
+	}, []string{"category"})
+
+	Overrides = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fire_overrides_total",
+		Help: "Moderator score overrides, by new label.",
+	}, []string{"label"})
+
+	// FIREScores is observed once per newly scored article
+	FIREScores = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fire_score",
+		Help:    "FIRE scores of newly scored articles, by category and model version.",
+		Buckets: prometheus.LinearBuckets(10, 10, 9),
+	}, []string{"category", "model_version"})
+)
+
+// ObserveSince records the time elapsed since start in a histogram
+func ObserveSince(h prometheus.Observer, start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// queueDepthCollector reports the moderation queue length, counting it at most once per interval
+// so frequent scrapes don't turn into frequent database queries
+type queueDepthCollector struct {
+	desc     *prometheus.Desc
+	count    func(ctx context.Context) (int, error)
+	interval time.Duration
+
+	mu        sync.Mutex
+	depth     int
+	checkedAt time.Time
+	ok        bool
+}
+
+// RegisterModerationQueueDepth exports fire_moderation_queue_depth using count, cached for interval
+func RegisterModerationQueueDepth(count func(ctx context.Context) (int, error), interval time.Duration) {
+	prometheus.MustRegister(&queueDepthCollector{
+		desc:     prometheus.NewDesc("fire_moderation_queue_depth", "Articles waiting for a moderator.", nil, nil),
+		count:    count,
+		interval: interval,
+	})
+}
+
+func (c *queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checkedAt) >= c.interval {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		c.checkedAt = time.Now()
+		depth, err := c.count(ctx)
+		if err != nil {
+			// Keep exporting the last known value rather than leaving a gap
+			slog.Error("Failed to count moderation queue", "error", err)
+		} else {
+			c.depth, c.ok = depth, true
+		}
+	}
+	if c.ok {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(c.depth))
+	}
+}
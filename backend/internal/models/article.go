@@ -11,8 +11,26 @@ type Article struct {
 	Source       string     `json:"source"`
 	Author       string     `json:"author,omitempty"`
 	PublishedAt  time.Time  `json:"published_at"`
+	SubmittedAt  time.Time  `json:"submitted_at"`
 	ModelVersion string     `json:"model_version,omitempty"`
 	FIREScore    *FIREScore `json:"fire_score,omitempty"`
+
+	// ModelScore is the score the model originally assigned. FIREScore holds the effective score,
+	// which differs from ModelScore once a moderator has overridden it.
+	ModelScore int `json:"model_score"`
+
+	// HeadlineScore and BodyScore are the model's scores for the title and the content that ModelScore
+	// combines; both are nil for articles scored before headlines were scored separately, and HeadlineScore
+	// is nil for an article without a title
+	HeadlineScore *int `json:"headline_score,omitempty"`
+	BodyScore     *int `json:"body_score,omitempty"`
+
+	// PartnerKeyID is the API key the article was submitted with, empty for other submissions
+	PartnerKeyID string `json:"partner_key_id,omitempty"`
+
+	// ReportCount and LatestReportAt aggregate the article's reports; LatestReportAt is zero if never reported
+	ReportCount    int       `json:"report_count"`
+	LatestReportAt time.Time `json:"latest_report_at"`
 }
 
 // FIREScore represents the fake news detection score
@@ -20,6 +38,43 @@ type FIREScore struct {
 	OverallScore int       `json:"overall_score"` // 0-100
 	Confidence   float64   `json:"confidence"`
 	Timestamp    time.Time `json:"timestamp"`
+	// ModelVersion is the model version that produced the score
+	ModelVersion string `json:"model_version,omitempty"`
+
+	// HeadlineScore and BodyScore are the title's and the content's scores, which OverallScore weights together.
+	// HeadlineScore is nil when the article has no title.
+	HeadlineScore *int `json:"headline_score,omitempty"`
+	BodyScore     *int `json:"body_score,omitempty"`
+
+	// Chunks are the scores of the overlapping windows a fresh prediction was made from, combined by Aggregation.
+	// They are returned with the prediction but not stored with the article.
+	Chunks      []ChunkScore `json:"chunks,omitempty"`
+	Aggregation string       `json:"aggregation,omitempty"`
+	// Truncated is set when the article was too long to score every window
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ChunkScore is the model's score for one window of an article's content
+type ChunkScore struct {
+	Index int `json:"index"`
+	// StartChar and EndChar locate the window in the content as character offsets, end exclusive
+	StartChar  int     `json:"start_char"`
+	EndChar    int     `json:"end_char"`
+	Score      int     `json:"score"`
+	Confidence float64 `json:"confidence"`
+}
+
+// PredictionInput is the article fields sent to the model
+type PredictionInput struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Source  string `json:"source,omitempty"`
+	Author  string `json:"author,omitempty"`
+}
+
+// PredictionInput returns the fields of the article the model scores
+func (a *Article) PredictionInput() PredictionInput {
+	return PredictionInput{Title: a.Title, Content: a.Content, Source: a.Source, Author: a.Author}
 }
 
 type CreateArticleRequest struct {
@@ -30,3 +85,36 @@ type CreateArticleRequest struct {
 	Author      string `json:"author"`
 	PublishedAt string `json:"publishedAt" binding:"required"`
 }
+
+// Sort fields accepted by ArticleQuery.SortBy
+const (
+	SortBySubmittedAt = "submitted_at"
+	SortByPublishedAt = "published_at"
+	SortByFIREScore   = "fire_score"
+)
+
+// ArticleQuery selects one page of articles. Zero values mean "no filter".
+type ArticleQuery struct {
+	PageSize  int
+	PageToken string // opaque cursor from a previous ArticlePage.NextPageToken
+
+	Source       string
+	Author       string
+	ModelVersion string
+	MinScore     *int // inclusive
+	MaxScore     *int // inclusive
+
+	PublishedFrom time.Time // inclusive
+	PublishedTo   time.Time // exclusive
+	SubmittedFrom time.Time // inclusive
+	SubmittedTo   time.Time // exclusive
+
+	SortBy     string // one of the SortBy constants, default submitted_at
+	Descending bool
+}
+
+// ArticlePage is one page of query results
+type ArticlePage struct {
+	Articles      []*Article
+	NextPageToken string // empty on the last page
+}
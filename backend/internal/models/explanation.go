@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Explanation attributes an article's FIRE score to the words of its headline and content
+type Explanation struct {
+	// Method is how the attributions were computed; currently always occlusion
+	Method       string        `json:"method"`
+	ModelVersion string        `json:"model_version"`
+	Attributions []Attribution `json:"attributions"`
+	// Truncated is set when the content was too long to explain in full
+	Truncated  bool      `json:"truncated,omitempty"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// Attribution is one word's contribution to the score
+type Attribution struct {
+	// Field is title or content; StartChar and EndChar locate the word in it, end exclusive
+	Field     string `json:"field"`
+	StartChar int    `json:"start_char"`
+	EndChar   int    `json:"end_char"`
+	Text      string `json:"text"`
+	// Weight is positive when the word pushed the score towards reliable and negative when it pushed towards fake
+	Weight float64 `json:"weight"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// JobStatus is the lifecycle state of an asynchronous submission
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job tracks an article submitted with async=true
+type Job struct {
+	ID        string     `json:"id"`
+	Status    JobStatus  `json:"status"`
+	ArticleID string     `json:"article_id,omitempty"`
+	FIREScore *FIREScore `json:"fire_score,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Owner is the subject of the principal that submitted the job, empty when authentication is disabled
+	Owner string `json:"-"`
+}
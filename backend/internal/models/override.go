@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Override is one entry in an article's append-only moderator override log
+type Override struct {
+	ID            string    `json:"id"`
+	ArticleID     string    `json:"article_id"`
+	PreviousScore int       `json:"previous_score"`
+	NewScore      int       `json:"new_score"`
+	NewLabel      string    `json:"new_label"`
+	Confidence    float64   `json:"confidence"`
+	ModeratorID   string    `json:"moderator_id"`
+	Notes         string    `json:"notes,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PartnerKey is an API key issued to a partner for the submit endpoints.
+// Only a hash of the key's secret is stored; the full key is shown once when it is created or rotated.
+type PartnerKey struct {
+	ID          string `json:"id"`
+	PartnerName string `json:"partner_name"`
+	// AllowedSources limits which article sources the key may submit; empty allows any source
+	AllowedSources []string `json:"allowed_sources"`
+	// Daily quotas reset at midnight UTC; 0 means unlimited
+	DailyRequestQuota int        `json:"daily_request_quota"`
+	DailyArticleQuota int        `json:"daily_article_quota"`
+	SecretHash        string     `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	RotatedAt         *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+}
+
+// PartnerUsage counts a key's requests and accepted articles on one UTC day (YYYY-MM-DD)
+type PartnerUsage struct {
+	KeyID    string `json:"partner_key_id"`
+	Day      string `json:"date"`
+	Requests int    `json:"requests"`
+	Articles int    `json:"articles"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Report reason categories accepted by POST /api/v1/articles/{id}/report
+const (
+	ReportIncorrectScore = "incorrect_score"
+	ReportMisleading     = "misleading"
+	ReportSatire         = "satire"
+	ReportSpam           = "spam"
+	ReportOther          = "other"
+)
+
+// Report is a single user report flagging an article for moderation
+type Report struct {
+	ID        string `json:"id"`
+	ArticleID string `json:"article_id"`
+	Category  string `json:"category"`
+	Reason    string `json:"reason,omitempty"`
+	// ReporterID identifies the reporter so repeat reports can be ignored:
+	// a hash of the client's address and user agent for anonymous users
+	ReporterID string    `json:"reporter_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
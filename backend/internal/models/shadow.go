@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ShadowScore is a candidate model's score of an article next to the score the active model gave it.
+// It is stored to evaluate the candidate before promoting it and never shown as the article's score.
+type ShadowScore struct {
+	ArticleID string `json:"article_id"`
+	// ModelVersion is the candidate version that produced Score
+	ModelVersion string  `json:"model_version"`
+	Score        int     `json:"score"`
+	Confidence   float64 `json:"confidence"`
+
+	// ActiveVersion and ActiveScore are the version that scored the article when it was submitted and its score,
+	// before any moderator override
+	ActiveVersion string `json:"active_version"`
+	ActiveScore   int    `json:"active_score"`
+
+	ScoredAt time.Time `json:"scored_at"`
+}
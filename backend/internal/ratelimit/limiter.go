@@ -0,0 +1,188 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Key selects what a rule's buckets are keyed by
+type Key string
+
+const (
+	// KeyIP gives every client address its own bucket
+	KeyIP Key = "ip"
+	// KeyAPIKey gives every partner API key its own bucket; other callers are keyed by address
+	KeyAPIKey Key = "api_key"
+	// KeyUser gives every authenticated caller (Firebase user or API key) its own bucket;
+	// anonymous callers are keyed by address
+	KeyUser Key = "user"
+)
+
+// Rule configures a token bucket: Requests tokens are added every Per, up to Burst
+type Rule struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+	Key      Key
+}
+
+// ParseRule parses "<requests>/<period>[,burst=<n>][,key=ip|api_key|user]", e.g. "30/m,burst=10,key=user".
+// The period is s, m, h or a Go duration such as 10s. Burst defaults to requests and key to ip.
+func ParseRule(s string) (Rule, error) {
+	fields := strings.Split(s, ",")
+	count, period, ok := strings.Cut(strings.TrimSpace(fields[0]), "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>", s)
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", s)
+	}
+	per, err := parsePeriod(period)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+
+	rule := Rule{Requests: requests, Per: per, Burst: requests, Key: KeyIP}
+	for _, field := range fields[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch name {
+		case "burst":
+			burst, err := strconv.Atoi(value)
+			if err != nil || burst <= 0 {
+				return Rule{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", s)
+			}
+			rule.Burst = burst
+		case "key":
+			switch Key(value) {
+			case KeyIP, KeyAPIKey, KeyUser:
+				rule.Key = Key(value)
+			default:
+				return Rule{}, fmt.Errorf("invalid rate limit %q: key must be ip, api_key or user", s)
+			}
+		default:
+			return Rule{}, fmt.Errorf("invalid rate limit %q: unknown option %q", s, name)
+		}
+	}
+	return rule, nil
+}
+
+func parsePeriod(period string) (time.Duration, error) {
+	switch period {
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("period must be s, m, h or a positive duration")
+	}
+	return d, nil
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%d per %s (burst %d, keyed by %s)", r.Requests, r.Per, r.Burst, r.Key)
+}
+
+// Limiter keeps a token bucket per key. Buckets that have refilled completely are forgotten,
+// so memory is bounded by the number of recently active clients.
+type Limiter struct {
+	rule Rule
+	// rate is tokens added per second
+	rate float64
+	now  func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewLimiter creates a limiter enforcing rule for every key
+func NewLimiter(rule Rule) *Limiter {
+	return &Limiter{
+		rule:    rule,
+		rate:    float64(rule.Requests) / rule.Per.Seconds(),
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Rule returns the limiter's configuration
+func (l *Limiter) Rule() Rule {
+	return l.rule
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it returns false
+// and how long until the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.rule.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// Wait returns how long until key's bucket has a token, without taking one; 0 if one is available now
+func (l *Limiter) Wait(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return 0
+	}
+	tokens := l.refill(b, l.now())
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / l.rate * float64(time.Second))
+}
+
+// refill returns the bucket's token count at now
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(float64(l.rule.Burst), b.tokens+elapsed*l.rate)
+}
+
+// sweep drops full buckets at most once a minute. l.mu must be held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.rule.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
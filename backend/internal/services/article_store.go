@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"backend/internal/models"
+)
+
+// ErrArticleNotFound is returned by an ArticleStore when no article exists with the requested ID
+var ErrArticleNotFound = errors.New("article not found")
+
+// ErrConcurrentUpdate is returned when concurrent writes to the same article kept the store from applying a change
+var ErrConcurrentUpdate = errors.New("article was updated concurrently, try again")
+
+// ErrDuplicateReport is returned by ReportArticle when the reporter has already reported the article
+var ErrDuplicateReport = errors.New("article already reported by this reporter")
+
+// ArticleStore is the persistence layer used by the HTTP handlers.
+// FirestoreService is the production implementation, SQLStore persists to SQLite or PostgreSQL,
+// and MemoryStore keeps everything in process so the backend can run offline.
+type ArticleStore interface {
+	SaveArticle(ctx context.Context, article *models.Article) (string, error)
+	SaveArticles(ctx context.Context, articles []*models.Article) []SaveResult
+	GetArticles(ctx context.Context, query models.ArticleQuery) (*models.ArticlePage, error)
+	GetArticleByID(ctx context.Context, id string) (*models.Article, error)
+	ReportArticle(ctx context.Context, report *models.Report) error
+	GetReports(ctx context.Context, articleID string) ([]*models.Report, error)
+	// ApplyModeratorOverride sets the article's effective score to override.NewScore, clears it from the
+	// moderation queue and appends the override to its history, filling in ID and PreviousScore
+	ApplyModeratorOverride(ctx context.Context, override *models.Override) error
+	GetOverrideHistory(ctx context.Context, articleID string) ([]*models.Override, error)
+	// SaveExplanation stores the explanation of the article's score, replacing any earlier one
+	SaveExplanation(ctx context.Context, articleID string, explanation *models.Explanation) error
+	// GetExplanation returns the stored explanation of the article's score, or nil if none has been computed
+	GetExplanation(ctx context.Context, articleID string) (*models.Explanation, error)
+	GetModeratorQueue(ctx context.Context, pageSize int, pageToken string) (*models.ArticlePage, error)
+	// CountModerationQueue returns how many articles are waiting for a moderator
+	CountModerationQueue(ctx context.Context) (int, error)
+	// Ping checks that the backing database is reachable
+	Ping(ctx context.Context) error
+}
+
+// Store is implemented by every storage backend: articles, partner API keys and shadow scores live side by side
+type Store interface {
+	ArticleStore
+	PartnerKeyStore
+	ShadowScoreStore
+}
+
+// SaveResult is the outcome of saving one article in a SaveArticles batch.
+// Exactly one of ID and Err is set.
+type SaveResult struct {
+	ID  string
+	Err error
+}
+
+const documentIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newDocumentID generates a 20 character ID in the same format as Firestore auto IDs
+func newDocumentID() string {
+	id := make([]byte, 20)
+	max := big.NewInt(int64(len(documentIDAlphabet)))
+	for i := range id {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		id[i] = documentIDAlphabet[n.Int64()]
+	}
+	return string(id)
+}
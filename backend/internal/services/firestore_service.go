@@ -2,42 +2,40 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
-	"sort"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
 	"backend/internal/models"
+	"backend/internal/tracing"
 )
 
-// Current model version for tracking predictions
-const ModelVersion = "v1.0.0"
-
 // FirestoreService handles database operations
 type FirestoreService struct {
 	projectID string
 }
 
 // No credentials needed when Firestore rules allow public access
-func NewFirestoreService() (*FirestoreService, error) {
-	projectID := os.Getenv("FIREBASE_PROJECT_ID")
-	if projectID == "" {
-		projectID = "deeplearningmilestone3"
-	}
-
+func NewFirestoreService(projectID string) (*FirestoreService, error) {
 	return &FirestoreService{
 		projectID: projectID,
 	}, nil
 }
 
 func toFirestoreFields(article *models.Article) map[string]interface{} {
-	return map[string]interface{}{
+	fields := map[string]interface{}{
 		"title":            map[string]interface{}{"stringValue": article.Title},
 		"content":          map[string]interface{}{"stringValue": article.Content},
 		"url":              map[string]interface{}{"stringValue": article.URL},
@@ -46,9 +44,18 @@ func toFirestoreFields(article *models.Article) map[string]interface{} {
 		"published_at":     map[string]interface{}{"timestampValue": article.PublishedAt.Format(time.RFC3339Nano)},
 		"submitted_at":     map[string]interface{}{"timestampValue": time.Now().Format(time.RFC3339Nano)},
 		"fire_score":       map[string]interface{}{"integerValue": article.FIREScore.OverallScore},
-		"model_version":    map[string]interface{}{"stringValue": ModelVersion},
+		"model_score":      map[string]interface{}{"integerValue": article.FIREScore.OverallScore},
+		"model_version":    map[string]interface{}{"stringValue": article.FIREScore.ModelVersion},
 		"needs_moderation": map[string]interface{}{"booleanValue": false},
+		"partner_key_id":   map[string]interface{}{"stringValue": article.PartnerKeyID},
+	}
+	if article.FIREScore.HeadlineScore != nil {
+		fields["headline_score"] = map[string]interface{}{"integerValue": *article.FIREScore.HeadlineScore}
+	}
+	if article.FIREScore.BodyScore != nil {
+		fields["body_score"] = map[string]interface{}{"integerValue": *article.FIREScore.BodyScore}
 	}
+	return fields
 }
 func getString(m map[string]interface{}, key string) string {
 	if v, ok := m[key].(map[string]interface{}); ok {
@@ -67,6 +74,27 @@ func getInt(m map[string]interface{}, key string) int {
 	}
 	return 0
 }
+
+// getOptionalInt is getInt for fields that older documents may lack; it returns nil for a missing field
+func getOptionalInt(m map[string]interface{}, key string) *int {
+	if _, ok := m[key]; !ok {
+		return nil
+	}
+	val := getInt(m, key)
+	return &val
+}
+func getFloat(m map[string]interface{}, key string) float64 {
+	if v, ok := m[key].(map[string]interface{}); ok {
+		if n, ok := v["doubleValue"].(float64); ok {
+			return n
+		}
+		if s, ok := v["integerValue"].(string); ok {
+			val, _ := strconv.ParseFloat(s, 64)
+			return val
+		}
+	}
+	return 0
+}
 func getTime(m map[string]interface{}, key string) time.Time {
 	if v, ok := m[key].(map[string]interface{}); ok {
 		if s, ok := v["timestampValue"].(string); ok {
@@ -77,18 +105,83 @@ func getTime(m map[string]interface{}, key string) time.Time {
 	return time.Time{}
 }
 
-func (s *FirestoreService) SaveArticle(article *models.Article) (string, error) {
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles", s.projectID)
+// documentsURL returns the REST URL for a document path relative to the database root
+func (s *FirestoreService) documentsURL(path string) string {
+	return fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/%s", s.projectID, path)
+}
 
-	payload := map[string]interface{}{
-		"fields": toFirestoreFields(article),
+// documentName returns the full resource name of a document, as used inside request bodies
+func (s *FirestoreService) documentName(path string) string {
+	return fmt.Sprintf("projects/%s/databases/(default)/documents/%s", s.projectID, path)
+}
+
+// doRequest sends a JSON request to the Firestore REST API and returns the response.
+// A nil payload sends an empty body.
+// The span covers the call until the response headers arrive.
+func (s *FirestoreService) doRequest(ctx context.Context, method, url string, payload interface{}) (resp *http.Response, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, firestoreSpanName(method, url), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(method), semconv.DBSystemKey.String("firestore")))
+	defer func() {
+		if err == nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, resp.Status)
+			}
+		}
+		tracing.EndSpan(span, err)
+	}()
+
+	var body io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(jsonData)
 	}
-	jsonData, err := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// firestoreSpanName names a call by its RPC (firestore runQuery) or, for document requests,
+// by method and collection (firestore GET articles)
+func firestoreSpanName(method, rawURL string) string {
+	_, path, _ := strings.Cut(rawURL, "/documents")
+	path, _, _ = strings.Cut(path, "?")
+	if rpc, ok := strings.CutPrefix(path, ":"); ok {
+		return "firestore " + rpc
+	}
+	collection, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return "firestore " + method + " " + collection
+}
+
+// Ping lists a single article ID to check that Firestore is reachable and the project's rules allow reads
+func (s *FirestoreService) Ping(ctx context.Context) error {
+	resp, err := s.doRequest(ctx, http.MethodGet, s.documentsURL("articles")+"?pageSize=1&mask.fieldPaths=title", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("firestore error: %s", string(bodyBytes))
 	}
+	return nil
+}
 
-	resp, err := http.Post(url, "application/json", bytes.NewReader(jsonData))
+func (s *FirestoreService) SaveArticle(ctx context.Context, article *models.Article) (string, error) {
+	payload := map[string]interface{}{
+		"fields": toFirestoreFields(article),
+	}
+	resp, err := s.doRequest(ctx, http.MethodPost, s.documentsURL("articles"), payload)
 	if err != nil {
 		return "", err
 	}
@@ -112,71 +205,140 @@ func (s *FirestoreService) SaveArticle(article *models.Article) (string, error)
 	return docID, nil
 }
 
-func (s *FirestoreService) GetArticles(limit int) ([]*models.Article, error) {
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles?pageSize=%d", s.projectID, limit)
+// firestoreBatchLimit is the maximum number of writes Firestore accepts in one batchWrite call
+const firestoreBatchLimit = 500
 
-	resp, err := http.Get(url)
+// SaveArticles stores articles with the batchWrite endpoint. Unlike a commit, batchWrite is not
+// atomic: each write succeeds or fails on its own and is reported in the matching SaveResult.
+func (s *FirestoreService) SaveArticles(ctx context.Context, articles []*models.Article) []SaveResult {
+	results := make([]SaveResult, len(articles))
+	for start := 0; start < len(articles); start += firestoreBatchLimit {
+		end := start + firestoreBatchLimit
+		if end > len(articles) {
+			end = len(articles)
+		}
+		s.batchWriteArticles(ctx, articles[start:end], results[start:end])
+	}
+	return results
+}
+
+func (s *FirestoreService) batchWriteArticles(ctx context.Context, articles []*models.Article, results []SaveResult) {
+	fail := func(err error) {
+		for i := range results {
+			results[i] = SaveResult{Err: err}
+		}
+	}
+
+	// batchWrite can't auto-generate IDs, so assign them here
+	ids := make([]string, len(articles))
+	writes := make([]map[string]interface{}, len(articles))
+	for i, article := range articles {
+		ids[i] = newDocumentID()
+		writes[i] = map[string]interface{}{
+			"update": map[string]interface{}{
+				"name":   s.documentName("articles/" + ids[i]),
+				"fields": toFirestoreFields(article),
+			},
+			"currentDocument": map[string]interface{}{"exists": false},
+		}
+	}
+
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:batchWrite", s.projectID)
+	resp, err := s.doRequest(ctx, http.MethodPost, url, map[string]interface{}{"writes": writes})
 	if err != nil {
-		return nil, err
+		fail(err)
+		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
+		fail(fmt.Errorf("firestore error: %s", string(bodyBytes)))
+		return
 	}
 
 	var result struct {
-		Documents []struct {
-			Name   string                 `json:"name"`
-			Fields map[string]interface{} `json:"fields"`
-		} `json:"documents"`
+		Status []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"status"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+		fail(err)
+		return
 	}
 
-	type sortableArticle struct {
-		Article     *models.Article
-		SubmittedAt time.Time
+	for i := range results {
+		if i < len(result.Status) && result.Status[i].Code != 0 {
+			results[i] = SaveResult{Err: fmt.Errorf("firestore error: %s", result.Status[i].Message)}
+			continue
+		}
+		results[i] = SaveResult{ID: ids[i]}
 	}
-	sortable := make([]sortableArticle, 0, len(result.Documents))
-	for _, doc := range result.Documents {
-		fields := doc.Fields
-		parts := strings.Split(doc.Name, "/")
-		docID := parts[len(parts)-1]
-		submittedAt := getTime(fields, "submitted_at")
-		article := &models.Article{
-			ID:           docID,
-			Title:        getString(fields, "title"),
-			Content:      getString(fields, "content"),
-			URL:          getString(fields, "url"),
-			Source:       getString(fields, "source"),
-			Author:       getString(fields, "author"),
-			PublishedAt:  getTime(fields, "published_at"),
+}
+
+// firestoreDocument is a document as returned by the REST API
+type firestoreDocument struct {
+	Name   string                 `json:"name"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// articleFromDocument converts a Firestore document in the articles collection to an Article
+func articleFromDocument(doc firestoreDocument) *models.Article {
+	fields := doc.Fields
+	parts := strings.Split(doc.Name, "/")
+	submittedAt := getTime(fields, "submitted_at")
+	// Articles saved before model_score existed were never overridden, so their fire_score is the model's
+	modelScore := getInt(fields, "fire_score")
+	if _, ok := fields["model_score"]; ok {
+		modelScore = getInt(fields, "model_score")
+	}
+	return &models.Article{
+		ID:           parts[len(parts)-1],
+		Title:        getString(fields, "title"),
+		Content:      getString(fields, "content"),
+		URL:          getString(fields, "url"),
+		Source:       getString(fields, "source"),
+		Author:       getString(fields, "author"),
+		PublishedAt:  getTime(fields, "published_at"),
+		SubmittedAt:  submittedAt,
+		ModelVersion: getString(fields, "model_version"),
+		FIREScore: &models.FIREScore{
+			OverallScore: getInt(fields, "fire_score"),
+			Timestamp:    submittedAt,
 			ModelVersion: getString(fields, "model_version"),
-			FIREScore: &models.FIREScore{
-				OverallScore: getInt(fields, "fire_score"),
-				Timestamp:    submittedAt,
-			},
-		}
-		sortable = append(sortable, sortableArticle{Article: article, SubmittedAt: submittedAt})
+		},
+		ModelScore:     modelScore,
+		HeadlineScore:  getOptionalInt(fields, "headline_score"),
+		BodyScore:      getOptionalInt(fields, "body_score"),
+		PartnerKeyID:   getString(fields, "partner_key_id"),
+		ReportCount:    getInt(fields, "report_count"),
+		LatestReportAt: getTime(fields, "latest_report_at"),
 	}
-	// Sort by submitted_at descending (most recent first)
-	sort.Slice(sortable, func(i, j int) bool {
-		return sortable[i].SubmittedAt.After(sortable[j].SubmittedAt)
-	})
-	articles := make([]*models.Article, 0, len(sortable))
-	for _, s := range sortable {
-		articles = append(articles, s.Article)
+}
+
+// reportFromDocument converts a Firestore document in an article's reports subcollection to a Report
+func reportFromDocument(articleID string, doc firestoreDocument) *models.Report {
+	parts := strings.Split(doc.Name, "/")
+	return &models.Report{
+		ID:         parts[len(parts)-1],
+		ArticleID:  articleID,
+		Category:   getString(doc.Fields, "category"),
+		Reason:     getString(doc.Fields, "reason"),
+		ReporterID: getString(doc.Fields, "reporter_id"),
+		CreatedAt:  getTime(doc.Fields, "created_at"),
 	}
-	return articles, nil
 }
 
-func (s *FirestoreService) GetArticleByID(id string) (*models.Article, error) {
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles/%s", s.projectID, id)
+// runQuery executes a structured query under parent, a document path or "" for the database root,
+// and returns the matching documents
+func (s *FirestoreService) runQuery(ctx context.Context, parent string, structuredQuery map[string]interface{}) ([]firestoreDocument, error) {
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:runQuery", s.projectID)
+	if parent != "" {
+		url = s.documentsURL(parent) + ":runQuery"
+	}
 
-	resp, err := http.Get(url)
+	resp, err := s.doRequest(ctx, http.MethodPost, url, map[string]interface{}{"structuredQuery": structuredQuery})
 	if err != nil {
 		return nil, err
 	}
@@ -187,124 +349,641 @@ func (s *FirestoreService) GetArticleByID(id string) (*models.Article, error) {
 		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
 	}
 
-	var doc struct {
-		Name   string                 `json:"name"`
-		Fields map[string]interface{} `json:"fields"`
+	// The response is a stream of results; entries without a document only carry read metadata
+	var results []struct {
+		Document *firestoreDocument `json:"document"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
 		return nil, err
 	}
-	fields := doc.Fields
+	docs := make([]firestoreDocument, 0, len(results))
+	for _, result := range results {
+		if result.Document != nil {
+			docs = append(docs, *result.Document)
+		}
+	}
+	return docs, nil
+}
 
-	article := &models.Article{
-		ID:           id,
-		Title:        getString(fields, "title"),
-		Content:      getString(fields, "content"),
-		URL:          getString(fields, "url"),
-		Source:       getString(fields, "source"),
-		Author:       getString(fields, "author"),
-		PublishedAt:  getTime(fields, "published_at"),
-		ModelVersion: getString(fields, "model_version"),
-		FIREScore: &models.FIREScore{
-			OverallScore: getInt(fields, "fire_score"),
-			Timestamp:    getTime(fields, "submitted_at"),
+// fieldFilter builds a structured query filter comparing a field with a value
+func fieldFilter(field, op string, value map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"fieldFilter": map[string]interface{}{
+			"field": map[string]interface{}{"fieldPath": field},
+			"op":    op,
+			"value": value,
 		},
 	}
+}
 
-	return article, nil
+// andFilters combines filters into a single where clause, or returns nil if there are none
+func andFilters(filters []map[string]interface{}) map[string]interface{} {
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	}
+	return map[string]interface{}{
+		"compositeFilter": map[string]interface{}{"op": "AND", "filters": filters},
+	}
+}
+
+func timestampValue(t time.Time) map[string]interface{} {
+	return map[string]interface{}{"timestampValue": t.UTC().Format(time.RFC3339Nano)}
 }
 
-func (s *FirestoreService) ReportArticle(articleID string) error {
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles/%s?updateMask.fieldPaths=needs_moderation", s.projectID, articleID)
+// GetArticles returns one page of articles matching the query.
+// Combining range filters on different fields needs a composite index; Firestore's error links to create it.
+func (s *FirestoreService) GetArticles(ctx context.Context, query models.ArticleQuery) (*models.ArticlePage, error) {
+	query = normalizeQuery(query)
+
+	var filters []map[string]interface{}
+	if query.Source != "" {
+		filters = append(filters, fieldFilter("source", "EQUAL", map[string]interface{}{"stringValue": query.Source}))
+	}
+	if query.Author != "" {
+		filters = append(filters, fieldFilter("author", "EQUAL", map[string]interface{}{"stringValue": query.Author}))
+	}
+	if query.ModelVersion != "" {
+		filters = append(filters, fieldFilter("model_version", "EQUAL", map[string]interface{}{"stringValue": query.ModelVersion}))
+	}
+	if query.MinScore != nil {
+		filters = append(filters, fieldFilter("fire_score", "GREATER_THAN_OR_EQUAL", map[string]interface{}{"integerValue": *query.MinScore}))
+	}
+	if query.MaxScore != nil {
+		filters = append(filters, fieldFilter("fire_score", "LESS_THAN_OR_EQUAL", map[string]interface{}{"integerValue": *query.MaxScore}))
+	}
+	if !query.PublishedFrom.IsZero() {
+		filters = append(filters, fieldFilter("published_at", "GREATER_THAN_OR_EQUAL", timestampValue(query.PublishedFrom)))
+	}
+	if !query.PublishedTo.IsZero() {
+		filters = append(filters, fieldFilter("published_at", "LESS_THAN", timestampValue(query.PublishedTo)))
+	}
+	if !query.SubmittedFrom.IsZero() {
+		filters = append(filters, fieldFilter("submitted_at", "GREATER_THAN_OR_EQUAL", timestampValue(query.SubmittedFrom)))
+	}
+	if !query.SubmittedTo.IsZero() {
+		filters = append(filters, fieldFilter("submitted_at", "LESS_THAN", timestampValue(query.SubmittedTo)))
+	}
+
+	structuredQuery, err := s.pagedQuery(query.PageToken, query.SortBy, query.Descending, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	if where := andFilters(filters); where != nil {
+		structuredQuery["where"] = where
+	}
+
+	docs, err := s.runQuery(ctx, "", structuredQuery)
+	if err != nil {
+		return nil, err
+	}
+	return s.articlePage(docs, query.SortBy, query.PageSize), nil
+}
+
+// pagedQuery builds a structured query over the articles collection ordered by sortBy then document name,
+// resuming after the page token if one is given. One extra document is requested to detect a next page.
+func (s *FirestoreService) pagedQuery(pageToken, sortBy string, descending bool, pageSize int) (map[string]interface{}, error) {
+	direction := "ASCENDING"
+	if descending {
+		direction = "DESCENDING"
+	}
+	structuredQuery := map[string]interface{}{
+		"from": []map[string]interface{}{{"collectionId": "articles"}},
+		"orderBy": []map[string]interface{}{
+			{"field": map[string]interface{}{"fieldPath": sortBy}, "direction": direction},
+			{"field": map[string]interface{}{"fieldPath": "__name__"}, "direction": direction},
+		},
+		"limit": pageSize + 1,
+	}
+
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken, sortBy)
+		if err != nil {
+			return nil, err
+		}
+		value, _ := cursor.typedValue()
+		var cursorValue map[string]interface{}
+		switch v := value.(type) {
+		case int:
+			cursorValue = map[string]interface{}{"integerValue": v}
+		case time.Time:
+			cursorValue = timestampValue(v)
+		}
+		structuredQuery["startAt"] = map[string]interface{}{
+			"values": []map[string]interface{}{
+				cursorValue,
+				{"referenceValue": s.documentName("articles/" + cursor.ID)},
+			},
+			// before=false starts strictly after the cursor
+			"before": false,
+		}
+	}
+	return structuredQuery, nil
+}
+
+// articlePage converts query results fetched with pagedQuery into a page
+func (s *FirestoreService) articlePage(docs []firestoreDocument, sortBy string, pageSize int) *models.ArticlePage {
+	page := &models.ArticlePage{Articles: make([]*models.Article, 0, len(docs))}
+	for _, doc := range docs {
+		page.Articles = append(page.Articles, articleFromDocument(doc))
+	}
+	if len(page.Articles) > pageSize {
+		page.Articles = page.Articles[:pageSize]
+		page.NextPageToken = encodePageToken(page.Articles[pageSize-1], sortBy)
+	}
+	return page
+}
+
+func (s *FirestoreService) GetArticleByID(ctx context.Context, id string) (*models.Article, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, s.documentsURL("articles/"+id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrArticleNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+
+	var doc firestoreDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return articleFromDocument(doc), nil
+}
+
+// ReportArticle stores the report in the article's reports subcollection and updates the article in one commit.
+// The report document is keyed by reporter ID, so a repeat report fails its exists=false precondition
+// and the whole commit, including the report_count increment, is rejected.
+func (s *FirestoreService) ReportArticle(ctx context.Context, report *models.Report) error {
+	articlePath := "articles/" + report.ArticleID
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:commit", s.projectID)
 
 	payload := map[string]interface{}{
-		"fields": map[string]interface{}{
-			"needs_moderation": map[string]interface{}{"booleanValue": true},
+		"writes": []map[string]interface{}{
+			{
+				"update": map[string]interface{}{
+					"name": s.documentName(articlePath + "/reports/" + report.ReporterID),
+					"fields": map[string]interface{}{
+						"category":    map[string]interface{}{"stringValue": report.Category},
+						"reason":      map[string]interface{}{"stringValue": report.Reason},
+						"reporter_id": map[string]interface{}{"stringValue": report.ReporterID},
+						"created_at":  timestampValue(report.CreatedAt),
+					},
+				},
+				"currentDocument": map[string]interface{}{"exists": false},
+			},
+			{
+				// currentDocument.exists stops the update from creating a stub document for unknown IDs
+				"update": map[string]interface{}{
+					"name": s.documentName(articlePath),
+					"fields": map[string]interface{}{
+						"needs_moderation": map[string]interface{}{"booleanValue": true},
+						"latest_report_at": timestampValue(report.CreatedAt),
+					},
+				},
+				"updateMask": map[string]interface{}{"fieldPaths": []string{"needs_moderation", "latest_report_at"}},
+				"updateTransforms": []map[string]interface{}{
+					{"fieldPath": "report_count", "increment": map[string]interface{}{"integerValue": "1"}},
+				},
+				"currentDocument": map[string]interface{}{"exists": true},
+			},
 		},
 	}
-	jsonData, err := json.Marshal(payload)
+	resp, err := s.doRequest(ctx, http.MethodPost, url, payload)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrArticleNotFound
+	case http.StatusConflict:
+		return ErrDuplicateReport
+	default:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+
+	slog.DebugContext(ctx, "Article marked for moderation", "article_id", report.ArticleID)
+	return nil
+}
+
+// GetReports returns the article's reports, newest first
+func (s *FirestoreService) GetReports(ctx context.Context, articleID string) ([]*models.Report, error) {
+	if _, err := s.GetArticleByID(ctx, articleID); err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(jsonData))
+	structuredQuery := map[string]interface{}{
+		"from": []map[string]interface{}{{"collectionId": "reports"}},
+		"orderBy": []map[string]interface{}{
+			{"field": map[string]interface{}{"fieldPath": "created_at"}, "direction": "DESCENDING"},
+		},
+	}
+	docs, err := s.runQuery(ctx, "articles/"+articleID, structuredQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*models.Report, 0, len(docs))
+	for _, doc := range docs {
+		reports = append(reports, reportFromDocument(articleID, doc))
+	}
+	return reports, nil
+}
+
+// maxTransactionAttempts bounds how often a transaction is retried after Firestore aborts it
+// because a concurrent transaction touched the same documents
+const maxTransactionAttempts = 3
+
+// errTransactionAborted is returned for a transaction Firestore aborted (HTTP 409), which is worth retrying
+var errTransactionAborted = errors.New("firestore transaction aborted")
+
+// ApplyModeratorOverride updates an article with moderator's override and adds it to the overrides subcollection.
+// The previous score is read inside a transaction so concurrent overrides can't record a stale previous_score.
+// A transaction aborted by a concurrent override is retried; ErrConcurrentUpdate is returned if every attempt is.
+func (s *FirestoreService) ApplyModeratorOverride(ctx context.Context, override *models.Override) error {
+	for attempt := 1; ; attempt++ {
+		err := s.applyModeratorOverride(ctx, override)
+		if !errors.Is(err, errTransactionAborted) {
+			return err
+		}
+		if attempt == maxTransactionAttempts {
+			return ErrConcurrentUpdate
+		}
+		slog.DebugContext(ctx, "Override transaction aborted, retrying", "article_id", override.ArticleID, "attempt", attempt)
+		select {
+		case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// applyModeratorOverride makes one attempt at ApplyModeratorOverride, rolling its transaction back on failure
+func (s *FirestoreService) applyModeratorOverride(ctx context.Context, override *models.Override) (err error) {
+	transaction, err := s.beginTransaction(ctx)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	committed := false
+	defer func() {
+		if !committed {
+			s.rollback(ctx, transaction)
+		}
+	}()
 
-	resp, err := http.DefaultClient.Do(req)
+	articlePath := "articles/" + override.ArticleID
+	resp, err := s.doRequest(ctx, http.MethodGet, s.documentsURL(articlePath+"?transaction="+url.QueryEscape(transaction)), nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrArticleNotFound
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return errTransactionAborted
+	}
 	if resp.StatusCode != 200 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("firestore error: %s", string(bodyBytes))
 	}
+	var doc firestoreDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return err
+	}
 
-	log.Printf("Article %s marked for moderation", articleID)
+	id := newDocumentID()
+	previousScore := getInt(doc.Fields, "fire_score")
+	payload := map[string]interface{}{
+		"transaction": transaction,
+		"writes": []map[string]interface{}{
+			{
+				"update": map[string]interface{}{
+					"name": s.documentName(articlePath),
+					"fields": map[string]interface{}{
+						"fire_score":       map[string]interface{}{"integerValue": override.NewScore},
+						"needs_moderation": map[string]interface{}{"booleanValue": false},
+					},
+				},
+				"updateMask":      map[string]interface{}{"fieldPaths": []string{"fire_score", "needs_moderation"}},
+				"currentDocument": map[string]interface{}{"exists": true},
+			},
+			{
+				"update": map[string]interface{}{
+					"name": s.documentName(articlePath + "/overrides/" + id),
+					"fields": map[string]interface{}{
+						"previous_score": map[string]interface{}{"integerValue": previousScore},
+						"new_score":      map[string]interface{}{"integerValue": override.NewScore},
+						"new_label":      map[string]interface{}{"stringValue": override.NewLabel},
+						"confidence":     map[string]interface{}{"doubleValue": override.Confidence},
+						"moderator_id":   map[string]interface{}{"stringValue": override.ModeratorID},
+						"notes":          map[string]interface{}{"stringValue": override.Notes},
+						"created_at":     timestampValue(override.CreatedAt),
+					},
+				},
+				"currentDocument": map[string]interface{}{"exists": false},
+			},
+		},
+	}
+	commitURL := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:commit", s.projectID)
+	commitResp, err := s.doRequest(ctx, http.MethodPost, commitURL, payload)
+	if err != nil {
+		return err
+	}
+	defer commitResp.Body.Close()
+
+	if commitResp.StatusCode == http.StatusNotFound {
+		return ErrArticleNotFound
+	}
+	if commitResp.StatusCode == http.StatusConflict {
+		return errTransactionAborted
+	}
+	if commitResp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(commitResp.Body)
+		return fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+	committed = true
+	override.ID = id
+	override.PreviousScore = previousScore
+
+	slog.DebugContext(ctx, "Stored moderator override", "article_id", override.ArticleID, "new_score", override.NewScore)
 	return nil
 }
 
-// SaveModeratorNote adds a note to the mod_notes subcollection for an article
-func (s *FirestoreService) SaveModeratorNote(articleID string, note string, newLabel string) error {
-	if note == "" {
-		return nil
+// SaveExplanation stores the explanation as a JSON string field of the article document,
+// so it reads back exactly as written without mapping every attribution to Firestore values
+func (s *FirestoreService) SaveExplanation(ctx context.Context, articleID string, explanation *models.Explanation) error {
+	encoded, err := json.Marshal(explanation)
+	if err != nil {
+		return err
 	}
-	// Firestore REST API endpoint for subcollection
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles/%s/mod_notes", s.projectID, articleID)
-
-	// Use timestamp as document ID (optional, Firestore will auto-generate if omitted)
-	noteFieldName := fmt.Sprintf("note_%d", time.Now().UnixNano())
 	payload := map[string]interface{}{
 		"fields": map[string]interface{}{
-			noteFieldName: map[string]interface{}{"stringValue": note},
+			"explanation": map[string]interface{}{"stringValue": string(encoded)},
 		},
 	}
-	jsonData, err := json.Marshal(payload)
+	// currentDocument.exists stops the patch from creating a stub document for unknown IDs
+	resp, err := s.doRequest(ctx, http.MethodPatch,
+		s.documentsURL("articles/"+articleID)+"?updateMask.fieldPaths=explanation&currentDocument.exists=true", payload)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrArticleNotFound
+	}
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+	return nil
+}
 
-	resp, err := http.Post(url, "application/json", bytes.NewReader(jsonData))
+// GetExplanation returns the article's explanation, or nil if none has been stored
+func (s *FirestoreService) GetExplanation(ctx context.Context, articleID string) (*models.Explanation, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, s.documentsURL("articles/"+articleID)+"?mask.fieldPaths=explanation", nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrArticleNotFound
+	}
 	if resp.StatusCode != 200 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("firestore error: %s", string(bodyBytes))
+		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
 	}
 
-	log.Printf("Saved moderator note for article %s: %s=%s", articleID, noteFieldName, note)
-	return nil
+	var doc firestoreDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+	encoded := getString(doc.Fields, "explanation")
+	if encoded == "" {
+		return nil, nil
+	}
+	var explanation models.Explanation
+	if err := json.Unmarshal([]byte(encoded), &explanation); err != nil {
+		return nil, fmt.Errorf("article %s: invalid explanation: %w", articleID, err)
+	}
+	return &explanation, nil
 }
 
-// ApplyModeratorOverride updates an article with moderator's override
-func (s *FirestoreService) ApplyModeratorOverride(articleID string, newFIREScore int) error {
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles/%s?updateMask.fieldPaths=fire_score&updateMask.fieldPaths=needs_moderation", s.projectID, articleID)
+// beginTransaction starts a read-write transaction and returns its ID
+func (s *FirestoreService) beginTransaction(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:beginTransaction", s.projectID)
+	resp, err := s.doRequest(ctx, http.MethodPost, url, map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
 
-	payload := map[string]interface{}{
-		"fields": map[string]interface{}{
-			"fire_score":       map[string]interface{}{"integerValue": newFIREScore},
-			"needs_moderation": map[string]interface{}{"booleanValue": false},
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+	var result struct {
+		Transaction string `json:"transaction"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.Transaction, nil
+}
+
+// rollback abandons transaction so its locks are released now rather than when it expires.
+// It runs even if ctx has been cancelled; failures are only logged, as the transaction expires anyway.
+func (s *FirestoreService) rollback(ctx context.Context, transaction string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:rollback", s.projectID)
+	resp, err := s.doRequest(ctx, http.MethodPost, url, map[string]interface{}{"transaction": transaction})
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to roll back Firestore transaction", "error", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		slog.WarnContext(ctx, "Failed to roll back Firestore transaction", "error", string(bodyBytes))
+	}
+}
+
+// GetOverrideHistory returns the article's overrides, oldest first
+func (s *FirestoreService) GetOverrideHistory(ctx context.Context, articleID string) ([]*models.Override, error) {
+	if _, err := s.GetArticleByID(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	structuredQuery := map[string]interface{}{
+		"from": []map[string]interface{}{{"collectionId": "overrides"}},
+		"orderBy": []map[string]interface{}{
+			{"field": map[string]interface{}{"fieldPath": "created_at"}, "direction": "ASCENDING"},
 		},
 	}
-	jsonData, err := json.Marshal(payload)
+	docs, err := s.runQuery(ctx, "articles/"+articleID, structuredQuery)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(jsonData))
+	overrides := make([]*models.Override, 0, len(docs))
+	for _, doc := range docs {
+		parts := strings.Split(doc.Name, "/")
+		overrides = append(overrides, &models.Override{
+			ID:            parts[len(parts)-1],
+			ArticleID:     articleID,
+			PreviousScore: getInt(doc.Fields, "previous_score"),
+			NewScore:      getInt(doc.Fields, "new_score"),
+			NewLabel:      getString(doc.Fields, "new_label"),
+			Confidence:    getFloat(doc.Fields, "confidence"),
+			ModeratorID:   getString(doc.Fields, "moderator_id"),
+			Notes:         getString(doc.Fields, "notes"),
+			CreatedAt:     getTime(doc.Fields, "created_at"),
+		})
+	}
+	return overrides, nil
+}
+
+// GetModeratorQueue retrieves a page of articles that need moderation, sorted by fire_score ascending (lowest/worst first).
+// Filtering and ordering run in Firestore, which needs a composite index on (needs_moderation, fire_score).
+func (s *FirestoreService) GetModeratorQueue(ctx context.Context, pageSize int, pageToken string) (*models.ArticlePage, error) {
+	query := normalizeQuery(models.ArticleQuery{PageSize: pageSize, PageToken: pageToken, SortBy: models.SortByFIREScore})
+
+	structuredQuery, err := s.pagedQuery(query.PageToken, query.SortBy, false, query.PageSize)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	structuredQuery["where"] = fieldFilter("needs_moderation", "EQUAL", map[string]interface{}{"booleanValue": true})
+
+	docs, err := s.runQuery(ctx, "", structuredQuery)
+	if err != nil {
+		return nil, err
+	}
+	page := s.articlePage(docs, query.SortBy, query.PageSize)
+
+	return page, nil
+}
+
+// CountModerationQueue counts flagged articles with an aggregation query, so documents aren't transferred
+func (s *FirestoreService) CountModerationQueue(ctx context.Context) (int, error) {
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:runAggregationQuery", s.projectID)
+	payload := map[string]interface{}{
+		"structuredAggregationQuery": map[string]interface{}{
+			"structuredQuery": map[string]interface{}{
+				"from":  []map[string]interface{}{{"collectionId": "articles"}},
+				"where": fieldFilter("needs_moderation", "EQUAL", map[string]interface{}{"booleanValue": true}),
+			},
+			"aggregations": []map[string]interface{}{{"alias": "queued", "count": map[string]interface{}{}}},
+		},
 	}
-	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.doRequest(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
 
-	resp, err := http.DefaultClient.Do(req)
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+
+	var results []struct {
+		Result *struct {
+			AggregateFields struct {
+				Queued struct {
+					IntegerValue string `json:"integerValue"`
+				} `json:"queued"`
+			} `json:"aggregateFields"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, err
+	}
+	for _, r := range results {
+		if r.Result != nil {
+			return strconv.Atoi(r.Result.AggregateFields.Queued.IntegerValue)
+		}
+	}
+	return 0, errors.New("firestore returned no aggregation result")
+}
+
+// partnerKeyFields converts a partner key to Firestore fields; unset optional timestamps are omitted
+func partnerKeyFields(key *models.PartnerKey) map[string]interface{} {
+	sources := make([]map[string]interface{}, 0, len(key.AllowedSources))
+	for _, source := range key.AllowedSources {
+		sources = append(sources, map[string]interface{}{"stringValue": source})
+	}
+	fields := map[string]interface{}{
+		"partner_name":        map[string]interface{}{"stringValue": key.PartnerName},
+		"allowed_sources":     map[string]interface{}{"arrayValue": map[string]interface{}{"values": sources}},
+		"daily_request_quota": map[string]interface{}{"integerValue": key.DailyRequestQuota},
+		"daily_article_quota": map[string]interface{}{"integerValue": key.DailyArticleQuota},
+		"secret_hash":         map[string]interface{}{"stringValue": key.SecretHash},
+		"created_at":          timestampValue(key.CreatedAt),
+	}
+	if key.RotatedAt != nil {
+		fields["rotated_at"] = timestampValue(*key.RotatedAt)
+	}
+	if key.RevokedAt != nil {
+		fields["revoked_at"] = timestampValue(*key.RevokedAt)
+	}
+	return fields
+}
+
+// partnerKeyFromDocument converts a document in the partner_keys collection to a PartnerKey
+func partnerKeyFromDocument(doc firestoreDocument) *models.PartnerKey {
+	fields := doc.Fields
+	parts := strings.Split(doc.Name, "/")
+	key := &models.PartnerKey{
+		ID:                parts[len(parts)-1],
+		PartnerName:       getString(fields, "partner_name"),
+		AllowedSources:    []string{},
+		DailyRequestQuota: getInt(fields, "daily_request_quota"),
+		DailyArticleQuota: getInt(fields, "daily_article_quota"),
+		SecretHash:        getString(fields, "secret_hash"),
+		CreatedAt:         getTime(fields, "created_at"),
+	}
+	if v, ok := fields["allowed_sources"].(map[string]interface{}); ok {
+		if array, ok := v["arrayValue"].(map[string]interface{}); ok {
+			values, _ := array["values"].([]interface{})
+			for _, value := range values {
+				if s, ok := value.(map[string]interface{})["stringValue"].(string); ok {
+					key.AllowedSources = append(key.AllowedSources, s)
+				}
+			}
+		}
+	}
+	if t := getTime(fields, "rotated_at"); !t.IsZero() {
+		key.RotatedAt = &t
+	}
+	if t := getTime(fields, "revoked_at"); !t.IsZero() {
+		key.RevokedAt = &t
+	}
+	return key
+}
+
+// SavePartnerKey creates or replaces a document in the partner_keys collection
+func (s *FirestoreService) SavePartnerKey(ctx context.Context, key *models.PartnerKey) error {
+	payload := map[string]interface{}{"fields": partnerKeyFields(key)}
+	resp, err := s.doRequest(ctx, http.MethodPatch, s.documentsURL("partner_keys/"+key.ID), payload)
 	if err != nil {
 		return err
 	}
@@ -314,16 +993,72 @@ func (s *FirestoreService) ApplyModeratorOverride(articleID string, newFIREScore
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("firestore error: %s", string(bodyBytes))
 	}
-
-	log.Printf("Applied moderator override to article %s: new_fire_score=%d", articleID, newFIREScore)
 	return nil
 }
 
-// GetModeratorQueue retrieves articles that need moderation, sorted by fire_score ascending (lowest/worst first)
-func (s *FirestoreService) GetModeratorQueue(limit int) ([]*models.Article, error) {
-	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents/articles?pageSize=100", s.projectID)
+// GetPartnerKey returns the partner key with the given ID
+func (s *FirestoreService) GetPartnerKey(ctx context.Context, id string) (*models.PartnerKey, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, s.documentsURL("partner_keys/"+id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrPartnerKeyNotFound
+	}
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+
+	var doc firestoreDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return partnerKeyFromDocument(doc), nil
+}
 
-	resp, err := http.Get(url)
+// ListPartnerKeys returns every partner key, oldest first
+func (s *FirestoreService) ListPartnerKeys(ctx context.Context) ([]*models.PartnerKey, error) {
+	structuredQuery := map[string]interface{}{
+		"from": []map[string]interface{}{{"collectionId": "partner_keys"}},
+		"orderBy": []map[string]interface{}{
+			{"field": map[string]interface{}{"fieldPath": "created_at"}, "direction": "ASCENDING"},
+		},
+	}
+	docs, err := s.runQuery(ctx, "", structuredQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*models.PartnerKey, 0, len(docs))
+	for _, doc := range docs {
+		keys = append(keys, partnerKeyFromDocument(doc))
+	}
+	return keys, nil
+}
+
+// AddPartnerUsage increments the key's counters for day with server-side transforms and returns the new totals.
+// The usage document is partner_keys/{id}/usage/{day} and is created on first use.
+func (s *FirestoreService) AddPartnerUsage(ctx context.Context, keyID, day string, requests, articles int) (*models.PartnerUsage, error) {
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents:commit", s.projectID)
+	payload := map[string]interface{}{
+		"writes": []map[string]interface{}{
+			{
+				"update": map[string]interface{}{
+					"name":   s.documentName("partner_keys/" + keyID + "/usage/" + day),
+					"fields": map[string]interface{}{"day": map[string]interface{}{"stringValue": day}},
+				},
+				"updateMask": map[string]interface{}{"fieldPaths": []string{"day"}},
+				"updateTransforms": []map[string]interface{}{
+					{"fieldPath": "requests", "increment": map[string]interface{}{"integerValue": strconv.Itoa(requests)}},
+					{"fieldPath": "articles", "increment": map[string]interface{}{"integerValue": strconv.Itoa(articles)}},
+				},
+			},
+		},
+	}
+	resp, err := s.doRequest(ctx, http.MethodPost, url, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -334,60 +1069,108 @@ func (s *FirestoreService) GetModeratorQueue(limit int) ([]*models.Article, erro
 		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
 	}
 
+	// transformResults holds the value of each field after its transform, in request order
 	var result struct {
-		Documents []struct {
-			Name   string                 `json:"name"`
-			Fields map[string]interface{} `json:"fields"`
-		} `json:"documents"`
+		WriteResults []struct {
+			TransformResults []struct {
+				IntegerValue string `json:"integerValue"`
+			} `json:"transformResults"`
+		} `json:"writeResults"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
+	if len(result.WriteResults) != 1 || len(result.WriteResults[0].TransformResults) != 2 {
+		return nil, errors.New("firestore error: unexpected commit response")
+	}
+	values := result.WriteResults[0].TransformResults
+	usage := &models.PartnerUsage{KeyID: keyID, Day: day}
+	usage.Requests, _ = strconv.Atoi(values[0].IntegerValue)
+	usage.Articles, _ = strconv.Atoi(values[1].IntegerValue)
+	return usage, nil
+}
 
-	// Filter and sort in Go
-	type sortableArticle struct {
-		Article   *models.Article
-		FireScore int
-	}
-	var queue []sortableArticle
-	for _, doc := range result.Documents {
-		fields := doc.Fields
-		if needsMod, ok := fields["needs_moderation"].(map[string]interface{}); ok {
-			if needs, ok := needsMod["booleanValue"].(bool); ok && needs {
-				// Extract document ID from the name
-				parts := strings.Split(doc.Name, "/")
-				docID := parts[len(parts)-1]
-				fireScore := getInt(fields, "fire_score")
-				article := &models.Article{
-					ID:           docID,
-					Title:        getString(fields, "title"),
-					Content:      getString(fields, "content"),
-					URL:          getString(fields, "url"),
-					Source:       getString(fields, "source"),
-					Author:       getString(fields, "author"),
-					PublishedAt:  getTime(fields, "published_at"),
-					ModelVersion: getString(fields, "model_version"),
-					FIREScore: &models.FIREScore{
-						OverallScore: fireScore,
-						Timestamp:    getTime(fields, "submitted_at"),
-					},
-				}
-				queue = append(queue, sortableArticle{Article: article, FireScore: fireScore})
-			}
-		}
+// GetPartnerUsage reads the usage document partner_keys/{id}/usage/{day}
+func (s *FirestoreService) GetPartnerUsage(ctx context.Context, keyID, day string) (*models.PartnerUsage, error) {
+	resp, err := s.doRequest(ctx, http.MethodGet, s.documentsURL("partner_keys/"+keyID+"/usage/"+day), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	usage := &models.PartnerUsage{KeyID: keyID, Day: day}
+	if resp.StatusCode == http.StatusNotFound {
+		return usage, nil
+	}
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+
+	var doc firestoreDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+	usage.Requests = getInt(doc.Fields, "requests")
+	usage.Articles = getInt(doc.Fields, "articles")
+	return usage, nil
+}
+
+// SaveShadowScore creates or replaces the document shadow_scores/{version}_{article ID}
+func (s *FirestoreService) SaveShadowScore(ctx context.Context, score *models.ShadowScore) error {
+	payload := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"article_id":     map[string]interface{}{"stringValue": score.ArticleID},
+			"model_version":  map[string]interface{}{"stringValue": score.ModelVersion},
+			"score":          map[string]interface{}{"integerValue": score.Score},
+			"confidence":     map[string]interface{}{"doubleValue": score.Confidence},
+			"active_version": map[string]interface{}{"stringValue": score.ActiveVersion},
+			"active_score":   map[string]interface{}{"integerValue": score.ActiveScore},
+			"scored_at":      timestampValue(score.ScoredAt),
+		},
+	}
+	resp, err := s.doRequest(ctx, http.MethodPatch, s.documentsURL("shadow_scores/"+score.ModelVersion+"_"+score.ArticleID), payload)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	// Sort by fire_score ascending
-	sort.Slice(queue, func(i, j int) bool {
-		return queue[i].FireScore < queue[j].FireScore
-	})
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("firestore error: %s", string(bodyBytes))
+	}
+	return nil
+}
 
-	// Limit results
-	resultArticles := make([]*models.Article, 0, limit)
-	for i := 0; i < len(queue) && i < limit; i++ {
-		resultArticles = append(resultArticles, queue[i].Article)
+// GetShadowScores returns version's scores from since onwards, oldest first.
+// Filtering on model_version while ordering by scored_at needs a composite index; Firestore's error links to create it.
+func (s *FirestoreService) GetShadowScores(ctx context.Context, version string, since time.Time) ([]*models.ShadowScore, error) {
+	structuredQuery := map[string]interface{}{
+		"from": []map[string]interface{}{{"collectionId": "shadow_scores"}},
+		"where": andFilters([]map[string]interface{}{
+			fieldFilter("model_version", "EQUAL", map[string]interface{}{"stringValue": version}),
+			fieldFilter("scored_at", "GREATER_THAN_OR_EQUAL", timestampValue(since)),
+		}),
+		"orderBy": []map[string]interface{}{
+			{"field": map[string]interface{}{"fieldPath": "scored_at"}, "direction": "ASCENDING"},
+		},
+	}
+	docs, err := s.runQuery(ctx, "", structuredQuery)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Retrieved %d articles needing moderation", len(resultArticles))
-	return resultArticles, nil
+	scores := make([]*models.ShadowScore, 0, len(docs))
+	for _, doc := range docs {
+		scores = append(scores, &models.ShadowScore{
+			ArticleID:     getString(doc.Fields, "article_id"),
+			ModelVersion:  getString(doc.Fields, "model_version"),
+			Score:         getInt(doc.Fields, "score"),
+			Confidence:    getFloat(doc.Fields, "confidence"),
+			ActiveVersion: getString(doc.Fields, "active_version"),
+			ActiveScore:   getInt(doc.Fields, "active_score"),
+			ScoredAt:      getTime(doc.Fields, "scored_at"),
+		})
+	}
+	return scores, nil
 }
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend/internal/metrics"
+	"backend/internal/models"
+	"backend/internal/tracing"
+)
+
+// InstrumentedStore wraps a Store to record the latency and errors of every call in Prometheus
+// and trace each call as a span
+type InstrumentedStore struct {
+	store   Store
+	backend string
+}
+
+// NewInstrumentedStore wraps store, labelling its metrics with backend (firestore, sql or memory)
+func NewInstrumentedStore(store Store, backend string) *InstrumentedStore {
+	return &InstrumentedStore{store: store, backend: backend}
+}
+
+// Close closes the underlying store if it holds resources such as a connection pool
+func (s *InstrumentedStore) Close() error {
+	if closer, ok := s.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// start times and traces one call; the returned function records its outcome and must be called with the
+// call's error. Errors callers expect and handle, such as a missing article or a duplicate report,
+// aren't counted as failures.
+func (s *InstrumentedStore) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, "store."+operation, trace.WithAttributes(attribute.String("store.backend", s.backend)))
+	return ctx, func(err error) {
+		metrics.ObserveSince(metrics.StoreOperationDuration.WithLabelValues(s.backend, operation), start)
+		if err != nil && !errors.Is(err, ErrArticleNotFound) && !errors.Is(err, ErrDuplicateReport) && !errors.Is(err, ErrPartnerKeyNotFound) {
+			metrics.StoreErrors.WithLabelValues(s.backend, operation).Inc()
+			tracing.EndSpan(span, err)
+			return
+		}
+		span.End()
+	}
+}
+
+func (s *InstrumentedStore) SaveArticle(ctx context.Context, article *models.Article) (string, error) {
+	ctx, done := s.start(ctx, "save_article")
+	id, err := s.store.SaveArticle(ctx, article)
+	done(err)
+	return id, err
+}
+
+func (s *InstrumentedStore) SaveArticles(ctx context.Context, articles []*models.Article) []SaveResult {
+	ctx, done := s.start(ctx, "save_articles")
+	results := s.store.SaveArticles(ctx, articles)
+	var err error
+	for _, result := range results {
+		if result.Err != nil {
+			err = result.Err
+			break
+		}
+	}
+	done(err)
+	return results
+}
+
+func (s *InstrumentedStore) GetArticles(ctx context.Context, query models.ArticleQuery) (*models.ArticlePage, error) {
+	ctx, done := s.start(ctx, "get_articles")
+	page, err := s.store.GetArticles(ctx, query)
+	done(err)
+	return page, err
+}
+
+func (s *InstrumentedStore) GetArticleByID(ctx context.Context, id string) (*models.Article, error) {
+	ctx, done := s.start(ctx, "get_article")
+	article, err := s.store.GetArticleByID(ctx, id)
+	done(err)
+	return article, err
+}
+
+func (s *InstrumentedStore) ReportArticle(ctx context.Context, report *models.Report) error {
+	ctx, done := s.start(ctx, "report_article")
+	err := s.store.ReportArticle(ctx, report)
+	done(err)
+	return err
+}
+
+func (s *InstrumentedStore) GetReports(ctx context.Context, articleID string) ([]*models.Report, error) {
+	ctx, done := s.start(ctx, "get_reports")
+	reports, err := s.store.GetReports(ctx, articleID)
+	done(err)
+	return reports, err
+}
+
+func (s *InstrumentedStore) ApplyModeratorOverride(ctx context.Context, override *models.Override) error {
+	ctx, done := s.start(ctx, "apply_override")
+	err := s.store.ApplyModeratorOverride(ctx, override)
+	done(err)
+	return err
+}
+
+func (s *InstrumentedStore) GetOverrideHistory(ctx context.Context, articleID string) ([]*models.Override, error) {
+	ctx, done := s.start(ctx, "get_override_history")
+	overrides, err := s.store.GetOverrideHistory(ctx, articleID)
+	done(err)
+	return overrides, err
+}
+
+func (s *InstrumentedStore) SaveExplanation(ctx context.Context, articleID string, explanation *models.Explanation) error {
+	ctx, done := s.start(ctx, "save_explanation")
+	err := s.store.SaveExplanation(ctx, articleID, explanation)
+	done(err)
+	return err
+}
+
+func (s *InstrumentedStore) GetExplanation(ctx context.Context, articleID string) (*models.Explanation, error) {
+	ctx, done := s.start(ctx, "get_explanation")
+	explanation, err := s.store.GetExplanation(ctx, articleID)
+	done(err)
+	return explanation, err
+}
+
+func (s *InstrumentedStore) GetModeratorQueue(ctx context.Context, pageSize int, pageToken string) (*models.ArticlePage, error) {
+	ctx, done := s.start(ctx, "get_moderator_queue")
+	page, err := s.store.GetModeratorQueue(ctx, pageSize, pageToken)
+	done(err)
+	return page, err
+}
+
+func (s *InstrumentedStore) CountModerationQueue(ctx context.Context) (int, error) {
+	ctx, done := s.start(ctx, "count_moderation_queue")
+	n, err := s.store.CountModerationQueue(ctx)
+	done(err)
+	return n, err
+}
+
+func (s *InstrumentedStore) Ping(ctx context.Context) error {
+	ctx, done := s.start(ctx, "ping")
+	err := s.store.Ping(ctx)
+	done(err)
+	return err
+}
+
+func (s *InstrumentedStore) SavePartnerKey(ctx context.Context, key *models.PartnerKey) error {
+	ctx, done := s.start(ctx, "save_partner_key")
+	err := s.store.SavePartnerKey(ctx, key)
+	done(err)
+	return err
+}
+
+func (s *InstrumentedStore) GetPartnerKey(ctx context.Context, id string) (*models.PartnerKey, error) {
+	ctx, done := s.start(ctx, "get_partner_key")
+	key, err := s.store.GetPartnerKey(ctx, id)
+	done(err)
+	return key, err
+}
+
+func (s *InstrumentedStore) ListPartnerKeys(ctx context.Context) ([]*models.PartnerKey, error) {
+	ctx, done := s.start(ctx, "list_partner_keys")
+	keys, err := s.store.ListPartnerKeys(ctx)
+	done(err)
+	return keys, err
+}
+
+func (s *InstrumentedStore) AddPartnerUsage(ctx context.Context, keyID, day string, requests, articles int) (*models.PartnerUsage, error) {
+	ctx, done := s.start(ctx, "add_partner_usage")
+	usage, err := s.store.AddPartnerUsage(ctx, keyID, day, requests, articles)
+	done(err)
+	return usage, err
+}
+
+func (s *InstrumentedStore) GetPartnerUsage(ctx context.Context, keyID, day string) (*models.PartnerUsage, error) {
+	ctx, done := s.start(ctx, "get_partner_usage")
+	usage, err := s.store.GetPartnerUsage(ctx, keyID, day)
+	done(err)
+	return usage, err
+}
+
+func (s *InstrumentedStore) SaveShadowScore(ctx context.Context, score *models.ShadowScore) error {
+	ctx, done := s.start(ctx, "save_shadow_score")
+	err := s.store.SaveShadowScore(ctx, score)
+	done(err)
+	return err
+}
+
+func (s *InstrumentedStore) GetShadowScores(ctx context.Context, version string, since time.Time) ([]*models.ShadowScore, error) {
+	ctx, done := s.start(ctx, "get_shadow_scores")
+	scores, err := s.store.GetShadowScores(ctx, version, since)
+	done(err)
+	return scores, err
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend/internal/logging"
+	"backend/internal/models"
+	"backend/internal/tracing"
+)
+
+// ErrJobQueueFull is returned by Enqueue when the backlog is at capacity
+var ErrJobQueueFull = errors.New("job queue is full")
+
+// ErrJobQueueClosed is returned by Enqueue after Close has been called
+var ErrJobQueueClosed = errors.New("job queue is closed")
+
+// JobFunc scores and stores one article, returning the saved article ID and its FIRE score
+type JobFunc func(ctx context.Context) (string, *models.FIREScore, error)
+
+// JobQueue runs submissions in the background with a fixed number of workers.
+// Job state is kept in memory and finished jobs are forgotten after the retention period.
+type JobQueue struct {
+	mu        sync.Mutex
+	jobs      map[string]*models.Job
+	pending   chan queuedJob
+	retention time.Duration
+	closed    bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type queuedJob struct {
+	id        string
+	requestID string
+	// parent is the span of the request that queued the job, so the job's span joins its trace
+	parent trace.SpanContext
+	fn     JobFunc
+}
+
+// NewJobQueue starts workers goroutines that drain a backlog of up to capacity jobs
+func NewJobQueue(workers, capacity int, retention time.Duration) *JobQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &JobQueue{
+		jobs:      make(map[string]*models.Job),
+		pending:   make(chan queuedJob, capacity),
+		retention: retention,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	return q
+}
+
+// Enqueue records a pending job owned by owner and schedules fn to run. The returned job is a snapshot.
+// fn's context carries the request ID and trace of ctx but isn't cancelled with it, as the job outlives the request.
+func (q *JobQueue) Enqueue(ctx context.Context, owner string, fn JobFunc) (*models.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, ErrJobQueueClosed
+	}
+	q.pruneLocked()
+
+	now := time.Now()
+	job := &models.Job{
+		ID:        newDocumentID(),
+		Owner:     owner,
+		Status:    models.JobPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	select {
+	case q.pending <- queuedJob{id: job.ID, requestID: logging.RequestID(ctx), parent: trace.SpanContextFromContext(ctx), fn: fn}:
+	default:
+		return nil, ErrJobQueueFull
+	}
+	q.jobs[job.ID] = job
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Get returns a snapshot of the job with the given ID
+func (q *JobQueue) Get(id string) (*models.Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *job
+	return &snapshot, true
+}
+
+// Close stops accepting jobs and waits for queued and running jobs to finish
+func (q *JobQueue) Close() {
+	q.Shutdown(context.Background())
+}
+
+// Shutdown stops accepting jobs and waits for queued and running jobs to finish.
+// If ctx ends first the jobs' context is cancelled, so running jobs abort and queued jobs fail as soon
+// as they start; Shutdown still waits for the workers to exit and then returns ctx's error.
+func (q *JobQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.pending)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		slog.Warn("Job queue did not drain in time, cancelling remaining jobs", "error", ctx.Err(), "jobs", q.unfinished())
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// unfinished counts pending and running jobs
+func (q *JobQueue) unfinished() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, job := range q.jobs {
+		if job.Status == models.JobPending || job.Status == models.JobRunning {
+			n++
+		}
+	}
+	return n
+}
+
+func (q *JobQueue) worker() {
+	defer q.wg.Done()
+	for queued := range q.pending {
+		q.run(queued)
+	}
+}
+
+func (q *JobQueue) run(queued queuedJob) {
+	q.update(queued.id, func(job *models.Job) {
+		job.Status = models.JobRunning
+	})
+
+	ctx := q.ctx
+	if queued.requestID != "" {
+		ctx = logging.WithRequestID(ctx, queued.requestID)
+	}
+	ctx, span := tracing.Tracer().Start(trace.ContextWithSpanContext(ctx, queued.parent), "job.run",
+		trace.WithAttributes(attribute.String("job.id", queued.id)))
+	articleID, fireScore, err := queued.fn(ctx)
+	tracing.EndSpan(span, err)
+
+	q.update(queued.id, func(job *models.Job) {
+		if err != nil {
+			slog.WarnContext(ctx, "Job failed", "job_id", job.ID, "error", err)
+			job.Status = models.JobFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = models.JobSucceeded
+		job.ArticleID = articleID
+		job.FIREScore = fireScore
+	})
+}
+
+func (q *JobQueue) update(id string, fn func(job *models.Job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job, ok := q.jobs[id]; ok {
+		fn(job)
+		job.UpdatedAt = time.Now()
+	}
+}
+
+// pruneLocked drops finished jobs older than the retention period. q.mu must be held.
+func (q *JobQueue) pruneLocked() {
+	cutoff := time.Now().Add(-q.retention)
+	for id, job := range q.jobs {
+		finished := job.Status == models.JobSucceeded || job.Status == models.JobFailed
+		if finished && job.UpdatedAt.Before(cutoff) {
+			delete(q.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"backend/internal/models"
+)
+
+// MemoryStore is an in-process ArticleStore used for offline development.
+// Nothing is persisted; all data is lost when the server stops.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	articles map[string]*memoryArticle
+
+	partnerKeys  map[string]*models.PartnerKey
+	partnerUsage map[string]*models.PartnerUsage // keyed by key ID + "/" + day
+
+	shadowScores map[string]*models.ShadowScore // keyed by model version + "/" + article ID
+}
+
+type memoryArticle struct {
+	article         models.Article
+	needsModeration bool
+	reports         []*models.Report
+	overrides       []*models.Override
+	explanation     *models.Explanation
+}
+
+// NewMemoryStore creates an empty in-memory article store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		articles:     make(map[string]*memoryArticle),
+		partnerKeys:  make(map[string]*models.PartnerKey),
+		partnerUsage: make(map[string]*models.PartnerUsage),
+		shadowScores: make(map[string]*models.ShadowScore),
+	}
+}
+
+// Ping always succeeds; there is nothing to connect to
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// snapshot returns a copy of the stored article so callers can't mutate the store
+func (m *memoryArticle) snapshot() *models.Article {
+	article := m.article
+	if m.article.FIREScore != nil {
+		score := *m.article.FIREScore
+		score.Timestamp = m.article.SubmittedAt
+		article.FIREScore = &score
+	}
+	return &article
+}
+
+func (s *MemoryStore) SaveArticle(ctx context.Context, article *models.Article) (string, error) {
+	stored := *article
+	stored.ID = newDocumentID()
+	stored.SubmittedAt = time.Now()
+	score := models.FIREScore{}
+	if article.FIREScore != nil {
+		score = *article.FIREScore
+	}
+	stored.ModelVersion = score.ModelVersion
+	stored.FIREScore = &score
+	stored.ModelScore = score.OverallScore
+	stored.HeadlineScore, stored.BodyScore = score.HeadlineScore, score.BodyScore
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.articles[stored.ID] = &memoryArticle{
+		article: stored,
+	}
+	return stored.ID, nil
+}
+
+// SaveArticles stores each article independently
+func (s *MemoryStore) SaveArticles(ctx context.Context, articles []*models.Article) []SaveResult {
+	results := make([]SaveResult, len(articles))
+	for i, article := range articles {
+		results[i].ID, results[i].Err = s.SaveArticle(ctx, article)
+	}
+	return results
+}
+
+// GetArticles returns one page of articles matching the query
+func (s *MemoryStore) GetArticles(ctx context.Context, query models.ArticleQuery) (*models.ArticlePage, error) {
+	return s.page(normalizeQuery(query), func(a *memoryArticle) bool { return true })
+}
+
+// page returns one page of the articles accepted by include that also match the query's filters
+func (s *MemoryStore) page(query models.ArticleQuery, include func(a *memoryArticle) bool) (*models.ArticlePage, error) {
+	var cursor *models.Article
+	if query.PageToken != "" {
+		c, err := decodePageToken(query.PageToken, query.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		// A stand-in article carrying only the cursor's sort value and ID, for comparisons
+		cursor = &models.Article{ID: c.ID, FIREScore: &models.FIREScore{}}
+		value, _ := c.typedValue()
+		switch v := value.(type) {
+		case int:
+			cursor.FIREScore.OverallScore = v
+		case time.Time:
+			cursor.PublishedAt, cursor.SubmittedAt = v, v
+		}
+	}
+
+	// Comparisons against the cursor are flipped for descending order
+	less := func(a, b *models.Article) bool {
+		if query.Descending {
+			return compareArticles(a, b, query.SortBy) > 0
+		}
+		return compareArticles(a, b, query.SortBy) < 0
+	}
+
+	s.mu.RLock()
+	var matches []*models.Article
+	for _, a := range s.articles {
+		if !include(a) {
+			continue
+		}
+		article := a.snapshot()
+		if !matchesQuery(article, query) {
+			continue
+		}
+		if cursor != nil && !less(cursor, article) {
+			continue
+		}
+		matches = append(matches, article)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return less(matches[i], matches[j])
+	})
+
+	page := &models.ArticlePage{Articles: matches}
+	if len(matches) > query.PageSize {
+		page.Articles = matches[:query.PageSize]
+		page.NextPageToken = encodePageToken(page.Articles[query.PageSize-1], query.SortBy)
+	}
+	return page, nil
+}
+
+func (s *MemoryStore) GetArticleByID(ctx context.Context, id string) (*models.Article, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.articles[id]
+	if !ok {
+		return nil, ErrArticleNotFound
+	}
+	return a.snapshot(), nil
+}
+
+// ReportArticle records the report and flags the article for moderation.
+// A second report from the same reporter returns ErrDuplicateReport and changes nothing.
+func (s *MemoryStore) ReportArticle(ctx context.Context, report *models.Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articles[report.ArticleID]
+	if !ok {
+		return ErrArticleNotFound
+	}
+	for _, existing := range a.reports {
+		if existing.ReporterID == report.ReporterID {
+			return ErrDuplicateReport
+		}
+	}
+
+	stored := *report
+	stored.ID = newDocumentID()
+	a.reports = append(a.reports, &stored)
+	a.article.ReportCount++
+	a.article.LatestReportAt = stored.CreatedAt
+	a.needsModeration = true
+
+	slog.DebugContext(ctx, "Article marked for moderation", "article_id", report.ArticleID)
+	return nil
+}
+
+// GetReports returns the article's reports, newest first
+func (s *MemoryStore) GetReports(ctx context.Context, articleID string) ([]*models.Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.articles[articleID]
+	if !ok {
+		return nil, ErrArticleNotFound
+	}
+	reports := make([]*models.Report, 0, len(a.reports))
+	for i := len(a.reports) - 1; i >= 0; i-- {
+		report := *a.reports[i]
+		reports = append(reports, &report)
+	}
+	return reports, nil
+}
+
+// ApplyModeratorOverride updates an article with moderator's override and records it in the article's history
+func (s *MemoryStore) ApplyModeratorOverride(ctx context.Context, override *models.Override) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articles[override.ArticleID]
+	if !ok {
+		return ErrArticleNotFound
+	}
+	if a.article.FIREScore == nil {
+		a.article.FIREScore = &models.FIREScore{}
+	}
+	override.ID = newDocumentID()
+	override.PreviousScore = a.article.FIREScore.OverallScore
+	a.article.FIREScore.OverallScore = override.NewScore
+	a.needsModeration = false
+
+	stored := *override
+	a.overrides = append(a.overrides, &stored)
+
+	slog.DebugContext(ctx, "Stored moderator override", "article_id", override.ArticleID, "new_score", override.NewScore)
+	return nil
+}
+
+// SaveExplanation stores a copy of the explanation with the article
+func (s *MemoryStore) SaveExplanation(ctx context.Context, articleID string, explanation *models.Explanation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articles[articleID]
+	if !ok {
+		return ErrArticleNotFound
+	}
+	stored := *explanation
+	stored.Attributions = append([]models.Attribution{}, explanation.Attributions...)
+	a.explanation = &stored
+	return nil
+}
+
+// GetExplanation returns a copy of the article's explanation, or nil if none has been stored
+func (s *MemoryStore) GetExplanation(ctx context.Context, articleID string) (*models.Explanation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.articles[articleID]
+	if !ok {
+		return nil, ErrArticleNotFound
+	}
+	if a.explanation == nil {
+		return nil, nil
+	}
+	explanation := *a.explanation
+	explanation.Attributions = append([]models.Attribution{}, a.explanation.Attributions...)
+	return &explanation, nil
+}
+
+// GetOverrideHistory returns the article's overrides, oldest first
+func (s *MemoryStore) GetOverrideHistory(ctx context.Context, articleID string) ([]*models.Override, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.articles[articleID]
+	if !ok {
+		return nil, ErrArticleNotFound
+	}
+	overrides := make([]*models.Override, 0, len(a.overrides))
+	for _, override := range a.overrides {
+		stored := *override
+		overrides = append(overrides, &stored)
+	}
+	return overrides, nil
+}
+
+// GetModeratorQueue returns a page of articles that need moderation, sorted by fire_score ascending (lowest/worst first)
+func (s *MemoryStore) GetModeratorQueue(ctx context.Context, pageSize int, pageToken string) (*models.ArticlePage, error) {
+	query := normalizeQuery(models.ArticleQuery{PageSize: pageSize, PageToken: pageToken, SortBy: models.SortByFIREScore})
+	return s.page(query, func(a *memoryArticle) bool { return a.needsModeration })
+}
+
+// CountModerationQueue returns how many articles are flagged for moderation
+func (s *MemoryStore) CountModerationQueue(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := 0
+	for _, a := range s.articles {
+		if a.needsModeration {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// SavePartnerKey creates or replaces a partner key
+func (s *MemoryStore) SavePartnerKey(ctx context.Context, key *models.PartnerKey) error {
+	stored := *key
+	stored.AllowedSources = append([]string(nil), key.AllowedSources...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partnerKeys[key.ID] = &stored
+	return nil
+}
+
+// GetPartnerKey returns a copy of the partner key with the given ID
+func (s *MemoryStore) GetPartnerKey(ctx context.Context, id string) (*models.PartnerKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.partnerKeys[id]
+	if !ok {
+		return nil, ErrPartnerKeyNotFound
+	}
+	stored := *key
+	stored.AllowedSources = append([]string(nil), key.AllowedSources...)
+	return &stored, nil
+}
+
+// ListPartnerKeys returns every partner key, oldest first
+func (s *MemoryStore) ListPartnerKeys(ctx context.Context) ([]*models.PartnerKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*models.PartnerKey, 0, len(s.partnerKeys))
+	for _, key := range s.partnerKeys {
+		stored := *key
+		stored.AllowedSources = append([]string(nil), key.AllowedSources...)
+		keys = append(keys, &stored)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// AddPartnerUsage adds to the key's counters for day and returns the new totals
+func (s *MemoryStore) AddPartnerUsage(ctx context.Context, keyID, day string, requests, articles int) (*models.PartnerUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage, ok := s.partnerUsage[keyID+"/"+day]
+	if !ok {
+		usage = &models.PartnerUsage{KeyID: keyID, Day: day}
+		s.partnerUsage[keyID+"/"+day] = usage
+	}
+	usage.Requests += requests
+	usage.Articles += articles
+
+	snapshot := *usage
+	return &snapshot, nil
+}
+
+// GetPartnerUsage returns the key's counters for day
+func (s *MemoryStore) GetPartnerUsage(ctx context.Context, keyID, day string) (*models.PartnerUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	usage, ok := s.partnerUsage[keyID+"/"+day]
+	if !ok {
+		return &models.PartnerUsage{KeyID: keyID, Day: day}, nil
+	}
+	snapshot := *usage
+	return &snapshot, nil
+}
+
+// SaveShadowScore stores a copy of the score, replacing the article's earlier score by the same version
+func (s *MemoryStore) SaveShadowScore(ctx context.Context, score *models.ShadowScore) error {
+	stored := *score
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shadowScores[score.ModelVersion+"/"+score.ArticleID] = &stored
+	return nil
+}
+
+// GetShadowScores returns copies of version's scores from since onwards, oldest first
+func (s *MemoryStore) GetShadowScores(ctx context.Context, version string, since time.Time) ([]*models.ShadowScore, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scores := []*models.ShadowScore{}
+	for _, score := range s.shadowScores {
+		if score.ModelVersion == version && !score.ScoredAt.Before(since) {
+			stored := *score
+			scores = append(scores, &stored)
+		}
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].ScoredAt.Before(scores[j].ScoredAt) })
+	return scores, nil
+}
@@ -1,56 +1,683 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend/internal/metrics"
 	"backend/internal/models"
+	"backend/internal/tracing"
 )
 
-// MLService handles machine learning predictions
+// MLService handles machine learning predictions for one model version using a pool of long-lived
+// predict.py --server processes, so the model is loaded once per worker instead of per article
 type MLService struct {
-	pythonPath string
-	scriptPath string
+	model   ModelSpec
+	opts    MLOptions
+	workers chan *mlWorker
+	size    int
+	nextID  atomic.Uint64
+
+	// health is the outcome of the latest canary or successful prediction, reported by Ready
+	health struct {
+		sync.Mutex
+		err       error
+		checkedAt time.Time
+		checking  bool
+		// busy is set when the latest canary found no idle worker
+		busy        bool
+		lastSuccess time.Time
+	}
+}
+
+// healthInterval is how long a canary or successful prediction vouches for the pool before Ready
+// starts another canary in the background
+const healthInterval = 30 * time.Second
+
+// MLOptions are the settings shared by the worker pools of every model version
+type MLOptions struct {
+	PythonPath string
+	ScriptPath string
+	// Aggregation combines the scores of a long article's chunks: mean, min or confidence_weighted
+	Aggregation string
+	// HeadlineWeight is the share of the overall score that comes from the headline
+	HeadlineWeight float64
+	Workers        int
+	// Timeout limits each prediction from when it gets a worker that has loaded its model; waiting for one
+	// is only bounded by the caller's context
+	Timeout            time.Duration
+	ExplanationTimeout time.Duration
 }
 
 // MLPredictionResponse represents the JSON output from Python
 type MLPredictionResponse struct {
+	ID           uint64  `json:"id"`
 	OverallScore int     `json:"overall_score"`
 	Confidence   float64 `json:"confidence"`
+	Error        string  `json:"error,omitempty"`
+	// Chunks are the per-window scores that OverallScore aggregates
+	Chunks      []models.ChunkScore `json:"chunks,omitempty"`
+	Aggregation string              `json:"aggregation,omitempty"`
+	Truncated   bool                `json:"truncated,omitempty"`
+	// HeadlineScore is absent when the article has no title
+	HeadlineScore *int `json:"headline_score,omitempty"`
+	BodyScore     *int `json:"body_score,omitempty"`
+	// TokenizeMS and InferenceMS are how long predict.py spent on each step, for tracing
+	TokenizeMS  float64 `json:"tokenize_ms,omitempty"`
+	InferenceMS float64 `json:"inference_ms,omitempty"`
+	// Method and Attributions answer explanation requests
+	Method       string               `json:"method,omitempty"`
+	Attributions []models.Attribution `json:"attributions,omitempty"`
+	// Ready is only set on the line a worker prints once its model is loaded
+	Ready bool `json:"ready,omitempty"`
+}
+
+// mlRequest is one line written to a worker's stdin
+type mlRequest struct {
+	ID             uint64                 `json:"id"`
+	Article        models.PredictionInput `json:"article"`
+	Aggregation    string                 `json:"aggregation,omitempty"`
+	HeadlineWeight float64                `json:"headline_weight"`
+	// Explain asks for the article's word attributions instead of its score
+	Explain bool `json:"explain,omitempty"`
+}
+
+// errWorkerExited is returned when a worker process dies mid-request
+var errWorkerExited = errors.New("ml worker exited")
+
+// PredictionTimeoutError is returned when a prediction does not finish within the configured timeout
+type PredictionTimeoutError struct {
+	Timeout time.Duration
 }
 
-func NewMLService(pythonPath, scriptPath string) *MLService {
-	return &MLService{
-		pythonPath: pythonPath,
-		scriptPath: scriptPath,
+func (e *PredictionTimeoutError) Error() string {
+	return fmt.Sprintf("ml prediction timed out after %s", e.Timeout)
+}
+
+// PredictionError is returned when predict.py answers with {"error": ...} instead of a score
+type PredictionError struct {
+	Message string
+}
+
+func (e *PredictionError) Error() string {
+	return "ml prediction failed: " + e.Message
+}
+
+// mlWorker is a single predict.py --server process
+type mlWorker struct {
+	index     int
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan []byte
+	exited    chan struct{}
+	startedAt time.Time
+	// ready is closed once the process has loaded its model
+	ready chan struct{}
+}
+
+// NewMLService starts the Python workers for model, model.Workers of them or opts.Workers if that is unset.
+// Workers that fail to start are retried when they are next needed, so a missing interpreter surfaces
+// as a prediction error rather than at startup.
+func NewMLService(model ModelSpec, opts MLOptions) *MLService {
+	workerCount := opts.Workers
+	if model.Workers > 0 {
+		workerCount = model.Workers
+	}
+	if workerCount < 1 {
+		workerCount = 1
+	}
+
+	s := &MLService{
+		model:   model,
+		opts:    opts,
+		workers: make(chan *mlWorker, workerCount),
+		size:    workerCount,
+	}
+	for i := 0; i < workerCount; i++ {
+		w := &mlWorker{index: i}
+		if err := s.startWorker(context.Background(), w); err != nil {
+			slog.Error("Failed to start ML worker", "model_version", model.Version, "worker", i, "error", err)
+		}
+		s.workers <- w
 	}
+	return s
 }
 
-// PredictFIREScore calls the Python ML model and returns a FIRE score
-func (s *MLService) PredictFIREScore(articleText string) (*models.FIREScore, error) {
-	// Execute Python script with article text as argument
-	cmd := exec.Command(s.pythonPath, s.scriptPath, articleText)
+// startWorker launches the Python process for w and begins reading its stdout.
+// Its span only covers spawning the process; the model loads in the background until the worker prints ready.
+func (s *MLService) startWorker(ctx context.Context, w *mlWorker) (err error) {
+	_, span := tracing.Tracer().Start(ctx, "ml.start_worker", trace.WithAttributes(
+		attribute.Int("ml.worker", w.index),
+		attribute.String("ml.model_version", s.model.Version),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
 
-	// Capture stdout and stderr
-	output, err := cmd.CombinedOutput()
+	cmd := exec.Command(s.opts.PythonPath, s.opts.ScriptPath, "--server",
+		"--model", s.model.Checkpoint,
+		"--base-model", s.model.BaseModel,
+		"--max-length", strconv.Itoa(s.model.MaxLength))
+	cmd.Stderr = &workerLogWriter{model: s.model.Version, worker: w.index}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, fmt.Errorf("python execution failed: %w, output: %s", err, string(output))
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
 	}
 
-	// Parse JSON response from Python
-	var response MLPredictionResponse
-	if err := json.Unmarshal(output, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse ML response: %w, output: %s", err, string(output))
+	w.cmd = cmd
+	w.stdin = stdin
+	w.responses = make(chan []byte)
+	w.exited = make(chan struct{})
+	w.startedAt = time.Now()
+	w.ready = make(chan struct{})
+
+	responses, exited, ready := w.responses, w.exited, w.ready
+	go func() {
+		reader := bufio.NewReader(stdout)
+		loaded := false
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if !loaded && bytes.Contains(line, []byte(`"ready"`)) {
+					var msg struct{ Ready bool }
+					if json.Unmarshal(line, &msg) == nil && msg.Ready {
+						loaded = true
+						close(ready)
+					}
+				}
+				responses <- line
+			}
+			if err != nil {
+				break
+			}
+		}
+		close(responses)
+		cmd.Wait()
+		close(exited)
+	}()
+
+	slog.Info("Started ML worker", "model_version", s.model.Version, "worker", w.index, "pid", cmd.Process.Pid)
+	return nil
+}
+
+// workerLogWriter logs a worker's stderr one line at a time
+type workerLogWriter struct {
+	model   string
+	worker  int
+	partial []byte
+}
+
+func (l *workerLogWriter) Write(p []byte) (int, error) {
+	l.partial = append(l.partial, p...)
+	for {
+		i := bytes.IndexByte(l.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if line := bytes.TrimSpace(l.partial[:i]); len(line) > 0 {
+			slog.Info("ML worker stderr", "model_version", l.model, "worker", l.worker, "line", string(line))
+		}
+		l.partial = l.partial[i+1:]
 	}
+}
+
+// alive reports whether the worker process is running
+func (w *mlWorker) alive() bool {
+	if w.cmd == nil {
+		return false
+	}
+	select {
+	case <-w.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// isReady reports whether the worker has loaded its model
+func (w *mlWorker) isReady() bool {
+	select {
+	case <-w.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitReady waits for the worker to load its model, skipping anything it prints before then.
+// The worker has not been sent a request, so if ctx ends first it is left running.
+func (w *mlWorker) waitReady(ctx context.Context) error {
+	for {
+		select {
+		case <-w.ready:
+			return nil
+		case _, ok := <-w.responses:
+			if !ok {
+				<-w.exited
+				return errWorkerExited
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// kill terminates the worker process and waits for it to be reaped.
+// Unread output is discarded so the reader goroutine can finish.
+func (w *mlWorker) kill() {
+	if !w.alive() {
+		return
+	}
+	w.cmd.Process.Kill()
+	for range w.responses {
+	}
+	<-w.exited
+}
+
+// stop asks the worker to exit by closing its stdin and kills it if it is still running after timeout
+func (w *mlWorker) stop(timeout time.Duration) {
+	if !w.alive() {
+		return
+	}
+	w.stdin.Close()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case _, ok := <-w.responses:
+			if !ok {
+				<-w.exited
+				return
+			}
+		case <-timer.C:
+			w.kill()
+			return
+		}
+	}
+}
+
+// acquire takes an idle worker from the pool, restarting it if its process has died, and waits for it to
+// load its model. Only ctx limits the wait.
+func (s *MLService) acquire(ctx context.Context) (w *mlWorker, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ml.acquire_worker")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	select {
+	case w = <-s.workers:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if !w.alive() {
+		if w.cmd != nil {
+			slog.WarnContext(ctx, "ML worker exited, restarting", "model_version", s.model.Version, "worker", w.index)
+		}
+		if err := s.startWorker(ctx, w); err != nil {
+			s.workers <- w
+			return nil, fmt.Errorf("failed to start ML worker: %w", err)
+		}
+	}
+	if !w.isReady() {
+		if err := w.waitReady(ctx); err != nil {
+			s.workers <- w
+			return nil, err
+		}
+		// The request waited for the model to load
+		span.AddEvent("ml.worker_ready", trace.WithAttributes(
+			attribute.Float64("ml.worker_startup_seconds", time.Since(w.startedAt).Seconds())))
+	}
+	return w, nil
+}
+
+// release returns a worker to the pool
+func (s *MLService) release(w *mlWorker) {
+	s.workers <- w
+}
+
+// roundTrip sends one request to the worker and waits for the response with the matching ID.
+// Lines with other IDs (such as the startup ready message) are skipped.
+// If ctx is done first the worker is killed, since predict.py can't abandon a request part way through.
+func (w *mlWorker) roundTrip(ctx context.Context, req mlRequest) (response *MLPredictionResponse, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ml.roundtrip", trace.WithAttributes(attribute.Int("ml.worker", w.index)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.stdin.Write(append(payload, '\n')); err != nil {
+		w.kill()
+		return nil, fmt.Errorf("failed to write to ML worker: %w", err)
+	}
+
+	for {
+		var line []byte
+		var ok bool
+		select {
+		case line, ok = <-w.responses:
+		case <-ctx.Done():
+			slog.WarnContext(ctx, "ML request cancelled, killing worker", "worker", w.index, "ml_request_id", req.ID, "error", ctx.Err())
+			w.kill()
+			return nil, ctx.Err()
+		}
+		if !ok {
+			<-w.exited
+			return nil, errWorkerExited
+		}
+
+		response = &MLPredictionResponse{}
+		if err := json.Unmarshal(line, response); err != nil {
+			// Libraries occasionally print to stdout; anything that isn't a response is logged and skipped
+			slog.DebugContext(ctx, "ML worker output", "worker", w.index, "line", string(bytes.TrimSpace(line)))
+			continue
+		}
+		if response.Ready {
+			continue
+		}
+		if response.ID == req.ID {
+			recordWorkerSteps(ctx, response)
+			return response, nil
+		}
+	}
+}
+
+// recordWorkerSteps adds the tokenization and inference steps timed by predict.py as spans ending now,
+// back to back, so a trace shows where a slow prediction spent its time inside the worker
+func recordWorkerSteps(ctx context.Context, response *MLPredictionResponse) {
+	if response.TokenizeMS <= 0 && response.InferenceMS <= 0 {
+		return
+	}
+	end := time.Now()
+	inferenceStart := end.Add(-time.Duration(response.InferenceMS * float64(time.Millisecond)))
+	tokenizeStart := inferenceStart.Add(-time.Duration(response.TokenizeMS * float64(time.Millisecond)))
+
+	_, span := tracing.Tracer().Start(ctx, "ml.tokenize", trace.WithTimestamp(tokenizeStart))
+	span.End(trace.WithTimestamp(inferenceStart))
+	_, span = tracing.Tracer().Start(ctx, "ml.inference", trace.WithTimestamp(inferenceStart))
+	span.End(trace.WithTimestamp(end))
+}
+
+// PredictFIREScore sends the article to an idle worker and returns a FIRE score.
+// It returns a *PredictionTimeoutError if the configured timeout expires once the article is on a worker,
+// ctx.Err() if ctx ends first, and a *PredictionError if the model reports an error.
+func (s *MLService) PredictFIREScore(ctx context.Context, input models.PredictionInput) (fireScore *models.FIREScore, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ml.predict", trace.WithAttributes(
+		attribute.Int("ml.text_length", len(input.Content)),
+		attribute.Int("ml.title_length", len(input.Title)),
+		attribute.String("ml.model_version", s.model.Version),
+		attribute.String("ml.aggregation", s.opts.Aggregation),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	start := time.Now()
+	response, err := s.run(ctx, mlRequest{Article: input, Aggregation: s.opts.Aggregation, HeadlineWeight: s.opts.HeadlineWeight}, s.opts.Timeout)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		observePrediction(start, "timeout")
+		return nil, &PredictionTimeoutError{Timeout: s.opts.Timeout}
+	}
+	if ctx.Err() != nil {
+		observePrediction(start, "cancelled")
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		observePrediction(start, "worker_error")
+		return nil, err
+	}
+	if response.Error != "" {
+		observePrediction(start, "model_error")
+		return nil, &PredictionError{Message: response.Error}
+	}
+	observePrediction(start, "")
+	s.recordHealth(nil)
+	span.SetAttributes(attribute.Int("ml.chunks", len(response.Chunks)), attribute.Bool("ml.truncated", response.Truncated))
 
 	// Create FIREScore model
-	fireScore := &models.FIREScore{
+	fireScore = &models.FIREScore{
 		OverallScore: response.OverallScore,
 		Confidence:   response.Confidence,
 		Timestamp:    time.Now(),
+		ModelVersion: s.model.Version,
+		Chunks:       response.Chunks,
+		Aggregation:  response.Aggregation,
+		Truncated:    response.Truncated,
+
+		HeadlineScore: response.HeadlineScore,
+		BodyScore:     response.BodyScore,
 	}
 
 	return fireScore, nil
 }
+
+// Explain computes which words of the article drove its score, by hiding one word at a time on a worker.
+// It is much slower than PredictFIREScore, occupying the worker for up to the explanation timeout,
+// and returns the same errors.
+func (s *MLService) Explain(ctx context.Context, input models.PredictionInput) (explanation *models.Explanation, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ml.explain", trace.WithAttributes(
+		attribute.Int("ml.text_length", len(input.Content)),
+		attribute.Int("ml.title_length", len(input.Title)),
+		attribute.String("ml.model_version", s.model.Version),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	start := time.Now()
+	outcome := "failure"
+	defer func() { metrics.ObserveSince(metrics.MLExplanationDuration.WithLabelValues(outcome), start) }()
+
+	response, err := s.run(ctx, mlRequest{Article: input, HeadlineWeight: s.opts.HeadlineWeight, Explain: true}, s.opts.ExplanationTimeout)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, &PredictionTimeoutError{Timeout: s.opts.ExplanationTimeout}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, &PredictionError{Message: response.Error}
+	}
+	outcome = "success"
+
+	if response.Attributions == nil {
+		response.Attributions = []models.Attribution{}
+	}
+	return &models.Explanation{
+		Method:       response.Method,
+		ModelVersion: s.model.Version,
+		Attributions: response.Attributions,
+		Truncated:    response.Truncated,
+		ComputedAt:   time.Now(),
+	}, nil
+}
+
+// observePrediction records a prediction's latency and, unless failure is empty, why it failed
+func observePrediction(start time.Time, failure string) {
+	outcome := "success"
+	if failure != "" {
+		outcome = "failure"
+		metrics.MLPredictionFailures.WithLabelValues(failure).Inc()
+	}
+	metrics.ObserveSince(metrics.MLPredictionDuration.WithLabelValues(outcome), start)
+}
+
+// canaryArticle is scored by Canary; its score doesn't matter, only that one comes back
+var canaryArticle = models.PredictionInput{
+	Title:   "Canary: city council approves library budget",
+	Content: "Canary: city council approves budget for new public library after months of debate.",
+}
+
+// Canary scores a fixed text to check that a worker can run the model end to end, waiting for a worker
+// like any prediction. Its outcome is what Ready reports until the next check.
+func (s *MLService) Canary(ctx context.Context) error {
+	fireScore, err := s.PredictFIREScore(ctx, canaryArticle)
+	if err == nil {
+		err = checkCanaryScore(fireScore.OverallScore)
+	}
+	if !errors.Is(err, context.Canceled) {
+		s.recordHealth(err)
+	}
+	return err
+}
+
+func checkCanaryScore(score int) error {
+	if score < 0 || score > 100 {
+		return fmt.Errorf("canary score %d is outside 0-100", score)
+	}
+	return nil
+}
+
+// Ready reports the outcome of the latest canary or successful prediction without running one, so a
+// readiness probe never waits for a worker or kills one. Once that outcome is older than healthInterval
+// a canary is started in the background to refresh it.
+func (s *MLService) Ready(ctx context.Context) error {
+	s.health.Lock()
+	defer s.health.Unlock()
+
+	if time.Since(s.health.checkedAt) >= healthInterval && !s.health.checking {
+		s.health.checking = true
+		go s.backgroundCanary()
+	}
+	if s.health.checkedAt.IsZero() {
+		return errors.New("no canary has completed yet")
+	}
+	if s.health.busy {
+		// A saturated pool is healthy as long as its predictions keep succeeding
+		switch {
+		case s.health.lastSuccess.IsZero():
+			return errors.New("all workers are busy and no prediction has succeeded yet")
+		case time.Since(s.health.lastSuccess) >= healthInterval+s.opts.Timeout:
+			return fmt.Errorf("all workers are busy, last success at %s", s.health.lastSuccess.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}
+	if s.health.err != nil {
+		return fmt.Errorf("last check %s ago: %w", time.Since(s.health.checkedAt).Round(time.Second), s.health.err)
+	}
+	return nil
+}
+
+// recordHealth stores the outcome of a canary or successful prediction for Ready
+func (s *MLService) recordHealth(err error) {
+	s.health.Lock()
+	defer s.health.Unlock()
+	s.health.err = err
+	s.health.checkedAt = time.Now()
+	s.health.busy = false
+	if err == nil {
+		s.health.lastSuccess = s.health.checkedAt
+	}
+}
+
+// recordBusy notes that a canary found every worker busy, so Ready judges the pool by its last success
+// instead of repeating an older outcome
+func (s *MLService) recordBusy() {
+	s.health.Lock()
+	defer s.health.Unlock()
+	s.health.checkedAt = time.Now()
+	s.health.busy = true
+}
+
+// backgroundCanary scores the canary text on an idle worker that has loaded its model, limited by the
+// prediction timeout. It doesn't wait when every worker is busy or still loading: busy workers report
+// their health through the predictions they complete, and a worker still loading is reported as such.
+func (s *MLService) backgroundCanary() {
+	defer func() {
+		s.health.Lock()
+		s.health.checking = false
+		s.health.Unlock()
+	}()
+
+	var w *mlWorker
+	select {
+	case w = <-s.workers:
+	default:
+		s.recordBusy()
+		return
+	}
+	defer s.release(w)
+
+	ctx := context.Background()
+	if !w.alive() {
+		if w.cmd != nil {
+			slog.Warn("ML worker exited, restarting", "model_version", s.model.Version, "worker", w.index)
+			s.recordHealth(fmt.Errorf("ML worker %d exited and was restarted", w.index))
+		}
+		if err := s.startWorker(ctx, w); err != nil {
+			s.recordHealth(fmt.Errorf("failed to start ML worker: %w", err))
+		}
+		return
+	}
+	if !w.isReady() {
+		s.recordHealth(fmt.Errorf("ML worker %d is still loading its model", w.index))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+	response, err := w.roundTrip(ctx, mlRequest{
+		ID:             s.nextID.Add(1),
+		Article:        canaryArticle,
+		Aggregation:    s.opts.Aggregation,
+		HeadlineWeight: s.opts.HeadlineWeight,
+	})
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		err = &PredictionTimeoutError{Timeout: s.opts.Timeout}
+	case err == nil && response.Error != "":
+		err = &PredictionError{Message: response.Error}
+	case err == nil:
+		err = checkCanaryScore(response.OverallScore)
+	}
+	s.recordHealth(err)
+}
+
+// Workers returns the size of the worker pool, i.e. how many predictions can run at once
+func (s *MLService) Workers() int {
+	return s.size
+}
+
+// run sends a single request, given the next request ID, to an idle worker that has loaded its model.
+// timeout (if positive) starts once the worker is acquired, so waiting for a busy or loading pool
+// never counts against it.
+func (s *MLService) run(ctx context.Context, req mlRequest, timeout time.Duration) (*MLPredictionResponse, error) {
+	w, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(w)
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req.ID = s.nextID.Add(1)
+	return w.roundTrip(ctx, req)
+}
+
+// Close stops every worker, waiting for in-flight predictions to finish first.
+// Closing stdin lets predict.py exit its read loop; workers that don't exit promptly are killed.
+func (s *MLService) Close() {
+	for i := 0; i < s.size; i++ {
+		w := <-s.workers
+		w.stop(5 * time.Second)
+	}
+}
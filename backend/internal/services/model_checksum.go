@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lfsPointerPrefix starts the small text file Git LFS checks out in place of a model that wasn't pulled
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/"
+
+// ModelChecksum verifies that the model file on disk is the expected version.
+// Hashing a model takes a while, so the result is cached until the file's size or modification time changes.
+type ModelChecksum struct {
+	path     string
+	expected string
+
+	mu      sync.Mutex
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+// NewModelChecksum creates a verifier for the model at path. expectedSHA256 may be empty to only
+// check that the file exists and isn't a Git LFS pointer.
+func NewModelChecksum(path, expectedSHA256 string) *ModelChecksum {
+	return &ModelChecksum{path: path, expected: strings.ToLower(expectedSHA256)}
+}
+
+// Verify hashes the model file and compares it with the expected checksum
+func (m *ModelChecksum) Verify(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return fmt.Errorf("model file: %w", err)
+	}
+	if m.sum == "" || info.Size() != m.size || !info.ModTime().Equal(m.modTime) {
+		sum, err := m.hash(ctx)
+		if err != nil {
+			return err
+		}
+		m.sum, m.size, m.modTime = sum, info.Size(), info.ModTime()
+	}
+
+	if m.expected != "" && m.sum != m.expected {
+		return fmt.Errorf("model checksum %s does not match expected %s", m.sum, m.expected)
+	}
+	return nil
+}
+
+// hash returns the file's SHA-256, or an error if it is a Git LFS pointer rather than the model
+func (m *ModelChecksum) hash(ctx context.Context) (string, error) {
+	f, err := os.Open(m.path)
+	if err != nil {
+		return "", fmt.Errorf("model file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	buf := make([]byte, 1<<20)
+	for first := true; ; first = false {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		n, err := f.Read(buf)
+		if first && bytes.HasPrefix(buf[:n], []byte(lfsPointerPrefix)) {
+			return "", fmt.Errorf("model file %s is a Git LFS pointer; run git lfs pull", m.path)
+		}
+		h.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("model file: %w", err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"backend/internal/models"
+)
+
+// ErrModelVersionNotFound is returned for a version that isn't in the registry
+var ErrModelVersionNotFound = errors.New("model version not found")
+
+// ModelManifest lists the model versions to load, which of them scores new articles and
+// which, if any, scores them in shadow
+type ModelManifest struct {
+	Active string `yaml:"active"`
+	// Shadow is a candidate version that also scores every new article; its scores are stored for
+	// comparison but never shown
+	Shadow string      `yaml:"shadow"`
+	Models []ModelSpec `yaml:"models"`
+}
+
+// ModelSpec describes one model version in the manifest
+type ModelSpec struct {
+	Version string `yaml:"version" json:"version"`
+	// Checkpoint is the fine-tuned weights; relative paths are resolved against the manifest's directory
+	Checkpoint string `yaml:"checkpoint" json:"checkpoint"`
+	// SHA256 is the checkpoint's expected checksum; empty skips the comparison
+	SHA256 string `yaml:"sha256" json:"sha256,omitempty"`
+	// BaseModel is the Hugging Face architecture and tokenizer the checkpoint was fine-tuned from
+	BaseModel string `yaml:"base_model" json:"base_model"`
+	// MaxLength is how many tokens the model reads at a time, including [CLS] and [SEP]
+	MaxLength int `yaml:"max_length" json:"max_length"`
+	// Thresholds label this version's scores; nil uses the configured thresholds
+	Thresholds *ModelThresholds `yaml:"thresholds" json:"thresholds,omitempty"`
+	// Workers overrides the configured worker pool size for this version; 0 uses the configured size
+	Workers int `yaml:"workers" json:"workers,omitempty"`
+}
+
+// ModelThresholds are the lowest scores labelled real and categorised unverified
+type ModelThresholds struct {
+	Real       int `yaml:"real" json:"real"`
+	Unverified int `yaml:"unverified" json:"unverified"`
+}
+
+var sha256Hex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// LoadModelManifest reads and validates the manifest at path, resolving checkpoint paths against its directory
+func LoadModelManifest(path string) (*ModelManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model manifest: %w", err)
+	}
+	var manifest ModelManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse model manifest %s: %w", path, err)
+	}
+
+	var errs []error
+	if len(manifest.Models) == 0 {
+		errs = append(errs, errors.New("models: at least one version is required"))
+	}
+	seen := make(map[string]bool, len(manifest.Models))
+	for i := range manifest.Models {
+		spec := &manifest.Models[i]
+		prefix := fmt.Sprintf("models[%d]", i)
+		if spec.Version == "" {
+			errs = append(errs, fmt.Errorf("%s.version: required", prefix))
+		} else if seen[spec.Version] {
+			errs = append(errs, fmt.Errorf("%s.version %q: listed more than once", prefix, spec.Version))
+		}
+		seen[spec.Version] = true
+		if spec.Checkpoint == "" {
+			errs = append(errs, fmt.Errorf("%s.checkpoint: required", prefix))
+		} else if !filepath.IsAbs(spec.Checkpoint) {
+			spec.Checkpoint = filepath.Join(filepath.Dir(path), spec.Checkpoint)
+		}
+		if spec.SHA256 != "" && !sha256Hex.MatchString(spec.SHA256) {
+			errs = append(errs, fmt.Errorf("%s.sha256: must be 64 hex characters", prefix))
+		}
+		if spec.BaseModel == "" {
+			errs = append(errs, fmt.Errorf("%s.base_model: required", prefix))
+		}
+		// Each window needs room for [CLS], [SEP] and more than the 32 tokens windows overlap by
+		if spec.MaxLength <= 34 || spec.MaxLength > 512 {
+			errs = append(errs, fmt.Errorf("%s.max_length %d: must be between 35 and 512", prefix, spec.MaxLength))
+		}
+		if t := spec.Thresholds; t != nil && (t.Unverified < 0 || t.Unverified > t.Real || t.Real > 100) {
+			errs = append(errs, fmt.Errorf("%s.thresholds: must satisfy 0 <= unverified <= real <= 100", prefix))
+		}
+		if spec.Workers < 0 {
+			errs = append(errs, fmt.Errorf("%s.workers %d: must not be negative", prefix, spec.Workers))
+		}
+	}
+	if !seen[manifest.Active] {
+		errs = append(errs, fmt.Errorf("active %q: must be one of the listed versions", manifest.Active))
+	}
+	if manifest.Shadow != "" && !seen[manifest.Shadow] {
+		errs = append(errs, fmt.Errorf("shadow %q: must be one of the listed versions", manifest.Shadow))
+	} else if manifest.Shadow != "" && manifest.Shadow == manifest.Active {
+		errs = append(errs, fmt.Errorf("shadow %q: must differ from the active version", manifest.Shadow))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid model manifest %s:\n%w", path, err)
+	}
+	return &manifest, nil
+}
+
+// RegisteredModel is a loaded model version with its own worker pool
+type RegisteredModel struct {
+	Spec     ModelSpec
+	ML       *MLService
+	Checksum *ModelChecksum
+}
+
+// ModelRegistry keeps every version in the manifest loaded side by side and sends new articles
+// to the active one, which can be switched without a restart
+type ModelRegistry struct {
+	models   map[string]*RegisteredModel
+	versions []string // manifest order
+
+	mu     sync.RWMutex
+	active string
+	shadow string
+}
+
+// NewModelRegistry starts a worker pool for every version in manifest
+func NewModelRegistry(manifest *ModelManifest, opts MLOptions) *ModelRegistry {
+	r := &ModelRegistry{models: make(map[string]*RegisteredModel, len(manifest.Models)), active: manifest.Active, shadow: manifest.Shadow}
+	for _, spec := range manifest.Models {
+		r.models[spec.Version] = &RegisteredModel{
+			Spec:     spec,
+			ML:       NewMLService(spec, opts),
+			Checksum: NewModelChecksum(spec.Checkpoint, spec.SHA256),
+		}
+		r.versions = append(r.versions, spec.Version)
+	}
+	return r
+}
+
+// Active returns the version that scores new articles
+func (r *ModelRegistry) Active() *RegisteredModel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.models[r.active]
+}
+
+// Shadow returns the candidate version that scores new articles in shadow, or nil if there is none.
+// A candidate that has since been activated no longer runs in shadow.
+func (r *ModelRegistry) Shadow() *RegisteredModel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.shadow == "" || r.shadow == r.active {
+		return nil
+	}
+	return r.models[r.shadow]
+}
+
+// Get returns a loaded version
+func (r *ModelRegistry) Get(version string) (*RegisteredModel, bool) {
+	m, ok := r.models[version]
+	return m, ok
+}
+
+// List returns every loaded version in manifest order
+func (r *ModelRegistry) List() []*RegisteredModel {
+	list := make([]*RegisteredModel, 0, len(r.versions))
+	for _, version := range r.versions {
+		list = append(list, r.models[version])
+	}
+	return list
+}
+
+// Activate makes version score new articles once its checkpoint passes the checksum and a canary prediction.
+// The switch lasts until restart; the manifest's active field decides the version at startup.
+func (r *ModelRegistry) Activate(ctx context.Context, version string) error {
+	m, ok := r.models[version]
+	if !ok {
+		return ErrModelVersionNotFound
+	}
+	if err := m.Checksum.Verify(ctx); err != nil {
+		return fmt.Errorf("model %s: %w", version, err)
+	}
+	if err := m.ML.Canary(ctx); err != nil {
+		return fmt.Errorf("model %s: %w", version, err)
+	}
+
+	r.mu.Lock()
+	previous := r.active
+	r.active = version
+	r.mu.Unlock()
+
+	slog.InfoContext(ctx, "Activated model version", "model_version", version, "previous_version", previous)
+	return nil
+}
+
+// PredictFIREScore scores the article with the active version
+func (r *ModelRegistry) PredictFIREScore(ctx context.Context, input models.PredictionInput) (*models.FIREScore, error) {
+	return r.Active().ML.PredictFIREScore(ctx, input)
+}
+
+// Ready reports whether the active version's workers are known to be scoring, without running a prediction
+func (r *ModelRegistry) Ready(ctx context.Context) error {
+	return r.Active().ML.Ready(ctx)
+}
+
+// VerifyChecksums checks every loaded version's checkpoint, reporting each failure with its version
+func (r *ModelRegistry) VerifyChecksums(ctx context.Context) error {
+	var errs []error
+	for _, m := range r.List() {
+		if err := m.Checksum.Verify(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("model %s: %w", m.Spec.Version, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops the workers of every version
+func (r *ModelRegistry) Close() {
+	for _, m := range r.List() {
+		m.ML.Close()
+	}
+}
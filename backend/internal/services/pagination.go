@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/internal/models"
+)
+
+// ErrInvalidPageToken is returned when a page token can't be decoded or belongs to a different sort order
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// pageCursor is the decoded form of a page token: the sort value and ID of the last article on the previous page
+type pageCursor struct {
+	SortBy string `json:"s"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+// sortValue returns the value of the article's sort field in cursor form
+func sortValue(article *models.Article, sortBy string) string {
+	switch sortBy {
+	case models.SortByPublishedAt:
+		return article.PublishedAt.UTC().Format(time.RFC3339Nano)
+	case models.SortByFIREScore:
+		if article.FIREScore == nil {
+			return "0"
+		}
+		return strconv.Itoa(article.FIREScore.OverallScore)
+	default:
+		return article.SubmittedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// encodePageToken builds the token that resumes after the given article
+func encodePageToken(article *models.Article, sortBy string) string {
+	data, _ := json.Marshal(pageCursor{SortBy: sortBy, Value: sortValue(article, sortBy), ID: article.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken parses a token produced by encodePageToken for the same sort field
+func decodePageToken(token, sortBy string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.SortBy != sortBy || cursor.ID == "" {
+		return nil, ErrInvalidPageToken
+	}
+	if _, err := cursor.typedValue(); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &cursor, nil
+}
+
+// typedValue returns the cursor value as a time.Time or int, matching the sort field's type
+func (c *pageCursor) typedValue() (interface{}, error) {
+	if c.SortBy == models.SortByFIREScore {
+		return strconv.Atoi(c.Value)
+	}
+	return time.Parse(time.RFC3339Nano, c.Value)
+}
+
+// normalizeQuery fills in the default sort field and page size
+func normalizeQuery(query models.ArticleQuery) models.ArticleQuery {
+	switch query.SortBy {
+	case models.SortByPublishedAt, models.SortByFIREScore:
+	default:
+		query.SortBy = models.SortBySubmittedAt
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 50
+	}
+	return query
+}
+
+// matchesQuery reports whether the article passes every filter in the query
+func matchesQuery(article *models.Article, query models.ArticleQuery) bool {
+	if query.Source != "" && article.Source != query.Source {
+		return false
+	}
+	if query.Author != "" && article.Author != query.Author {
+		return false
+	}
+	if query.ModelVersion != "" && article.ModelVersion != query.ModelVersion {
+		return false
+	}
+	score := 0
+	if article.FIREScore != nil {
+		score = article.FIREScore.OverallScore
+	}
+	if query.MinScore != nil && score < *query.MinScore {
+		return false
+	}
+	if query.MaxScore != nil && score > *query.MaxScore {
+		return false
+	}
+	if !inTimeRange(article.PublishedAt, query.PublishedFrom, query.PublishedTo) {
+		return false
+	}
+	return inTimeRange(article.SubmittedAt, query.SubmittedFrom, query.SubmittedTo)
+}
+
+func inTimeRange(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && !t.Before(to) {
+		return false
+	}
+	return true
+}
+
+// compareArticles orders two articles by the sort field, breaking ties by ID
+func compareArticles(a, b *models.Article, sortBy string) int {
+	var c int
+	switch sortBy {
+	case models.SortByPublishedAt:
+		c = a.PublishedAt.Compare(b.PublishedAt)
+	case models.SortByFIREScore:
+		c = a.FIREScore.OverallScore - b.FIREScore.OverallScore
+	default:
+		c = a.SubmittedAt.Compare(b.SubmittedAt)
+	}
+	if c == 0 {
+		switch {
+		case a.ID < b.ID:
+			c = -1
+		case a.ID > b.ID:
+			c = 1
+		}
+	}
+	return c
+}
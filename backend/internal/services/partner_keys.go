@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+)
+
+// ErrPartnerKeyNotFound is returned by a PartnerKeyStore when no key exists with the requested ID
+var ErrPartnerKeyNotFound = errors.New("partner key not found")
+
+// ErrInvalidAPIKey is returned by Authenticate for malformed, unknown, rotated or revoked keys
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// PartnerKeyStore persists partner API keys and their daily usage counters
+type PartnerKeyStore interface {
+	// SavePartnerKey creates the key or replaces the stored copy
+	SavePartnerKey(ctx context.Context, key *models.PartnerKey) error
+	GetPartnerKey(ctx context.Context, id string) (*models.PartnerKey, error)
+	ListPartnerKeys(ctx context.Context) ([]*models.PartnerKey, error)
+	// AddPartnerUsage atomically adds to the key's counters for day (which may be negative to refund)
+	// and returns the updated totals
+	AddPartnerUsage(ctx context.Context, keyID, day string, requests, articles int) (*models.PartnerUsage, error)
+	// GetPartnerUsage returns the key's counters for day, zero if nothing has been counted yet
+	GetPartnerUsage(ctx context.Context, keyID, day string) (*models.PartnerUsage, error)
+}
+
+// QuotaExceededError is returned when a key has used up one of its daily quotas
+type QuotaExceededError struct {
+	Quota string // "requests" or "articles"
+	Limit int
+	// ResetAt is when the quota's counters start again
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("daily %s quota of %d exceeded", e.Quota, e.Limit)
+}
+
+// partnerKeyPrefix starts every issued key so they are easy to recognise in logs and secret scanners
+const partnerKeyPrefix = "fire_"
+
+// PartnerKeyService issues partner API keys, authenticates requests made with them and enforces their quotas
+type PartnerKeyService struct {
+	store PartnerKeyStore
+	now   func() time.Time
+}
+
+// NewPartnerKeyService creates a service backed by store
+func NewPartnerKeyService(store PartnerKeyStore) *PartnerKeyService {
+	return &PartnerKeyService{store: store, now: time.Now}
+}
+
+// Create issues a new key and returns it with the full API key, which is not stored and can't be recovered
+func (s *PartnerKeyService) Create(ctx context.Context, key *models.PartnerKey) (string, error) {
+	secret, hash, err := newKeySecret()
+	if err != nil {
+		return "", err
+	}
+	key.ID = newDocumentID()
+	key.SecretHash = hash
+	key.CreatedAt = s.now().UTC()
+	key.RotatedAt = nil
+	key.RevokedAt = nil
+	if err := s.store.SavePartnerKey(ctx, key); err != nil {
+		return "", err
+	}
+
+	slog.InfoContext(ctx, "Created partner key", "partner_key_id", key.ID, "partner", key.PartnerName)
+	return formatAPIKey(key.ID, secret), nil
+}
+
+// Rotate replaces the key's secret, immediately invalidating the previous API key, and returns the new one
+func (s *PartnerKeyService) Rotate(ctx context.Context, id string) (*models.PartnerKey, string, error) {
+	key, err := s.store.GetPartnerKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.RevokedAt != nil {
+		return nil, "", ErrInvalidAPIKey
+	}
+
+	secret, hash, err := newKeySecret()
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now().UTC()
+	key.SecretHash = hash
+	key.RotatedAt = &now
+	if err := s.store.SavePartnerKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	slog.InfoContext(ctx, "Rotated partner key", "partner_key_id", key.ID, "partner", key.PartnerName)
+	return key, formatAPIKey(key.ID, secret), nil
+}
+
+// Revoke permanently disables the key. Revoking an already revoked key is a no-op.
+func (s *PartnerKeyService) Revoke(ctx context.Context, id string) (*models.PartnerKey, error) {
+	key, err := s.store.GetPartnerKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return key, nil
+	}
+
+	now := s.now().UTC()
+	key.RevokedAt = &now
+	if err := s.store.SavePartnerKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Revoked partner key", "partner_key_id", key.ID, "partner", key.PartnerName)
+	return key, nil
+}
+
+// SetAllowedSources replaces the sources the key may submit articles from; an empty list allows any source
+func (s *PartnerKeyService) SetAllowedSources(ctx context.Context, id string, sources []string) (*models.PartnerKey, error) {
+	key, err := s.store.GetPartnerKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key.AllowedSources = sources
+	if err := s.store.SavePartnerKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Changed partner key sources", "partner_key_id", key.ID, "partner", key.PartnerName, "sources", sources)
+	return key, nil
+}
+
+// List returns every issued key, including revoked ones
+func (s *PartnerKeyService) List(ctx context.Context) ([]*models.PartnerKey, error) {
+	return s.store.ListPartnerKeys(ctx)
+}
+
+// Authenticate returns the active key matching apiKey
+func (s *PartnerKeyService) Authenticate(ctx context.Context, apiKey string) (*models.PartnerKey, error) {
+	id, secret, ok := parseAPIKey(apiKey)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.store.GetPartnerKey(ctx, id)
+	if errors.Is(err, ErrPartnerKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil || subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+// CountRequest records one request made with the key, returning a QuotaExceededError once
+// the daily request quota is used up. Rejected requests still count.
+func (s *PartnerKeyService) CountRequest(ctx context.Context, key *models.PartnerKey) error {
+	day, resetAt := s.quotaDay()
+	usage, err := s.store.AddPartnerUsage(ctx, key.ID, day, 1, 0)
+	if err != nil {
+		return err
+	}
+	if key.DailyRequestQuota > 0 && usage.Requests > key.DailyRequestQuota {
+		return &QuotaExceededError{Quota: "requests", Limit: key.DailyRequestQuota, ResetAt: resetAt}
+	}
+	return nil
+}
+
+// ReserveArticles counts n articles against the key's daily article quota before they are scored.
+// If the reservation would exceed the quota nothing is counted and a QuotaExceededError is returned.
+// Articles that then fail to score or save should be handed back with ReleaseArticles.
+func (s *PartnerKeyService) ReserveArticles(ctx context.Context, key *models.PartnerKey, n int) error {
+	day, resetAt := s.quotaDay()
+	usage, err := s.store.AddPartnerUsage(ctx, key.ID, day, 0, n)
+	if err != nil {
+		return err
+	}
+	if key.DailyArticleQuota > 0 && usage.Articles > key.DailyArticleQuota {
+		s.ReleaseArticles(ctx, key, n)
+		return &QuotaExceededError{Quota: "articles", Limit: key.DailyArticleQuota, ResetAt: resetAt}
+	}
+	return nil
+}
+
+// ReleaseArticles returns n previously reserved articles to today's quota
+func (s *PartnerKeyService) ReleaseArticles(ctx context.Context, key *models.PartnerKey, n int) {
+	if n == 0 {
+		return
+	}
+	day, _ := s.quotaDay()
+	// The caller's context may already be cancelled, which is often why the articles are being released
+	if _, err := s.store.AddPartnerUsage(context.WithoutCancel(ctx), key.ID, day, 0, -n); err != nil {
+		slog.ErrorContext(ctx, "Failed to release reserved articles", "partner_key_id", key.ID, "articles", n, "error", err)
+	}
+}
+
+// Usage returns the key's counters for today
+func (s *PartnerKeyService) Usage(ctx context.Context, key *models.PartnerKey) (*models.PartnerUsage, error) {
+	day, _ := s.quotaDay()
+	return s.store.GetPartnerUsage(ctx, key.ID, day)
+}
+
+// quotaDay returns today's UTC date, which keys the usage counters, and when it ends
+func (s *PartnerKeyService) quotaDay() (string, time.Time) {
+	now := s.now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+}
+
+// SourceAllowed reports whether the key may submit articles from source
+func SourceAllowed(key *models.PartnerKey, source string) bool {
+	if len(key.AllowedSources) == 0 {
+		return true
+	}
+	for _, allowed := range key.AllowedSources {
+		if strings.EqualFold(allowed, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// newKeySecret generates a random secret and its stored hash
+func newKeySecret() (secret, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	return secret, hashSecret(secret), nil
+}
+
+// hashSecret hashes a key secret for storage. The secret is 256 random bits, so a fast hash is sufficient.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatAPIKey builds the key handed to the partner: fire_<key ID>.<secret>
+func formatAPIKey(id, secret string) string {
+	return partnerKeyPrefix + id + "." + secret
+}
+
+// parseAPIKey splits a key built by formatAPIKey
+func parseAPIKey(apiKey string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(apiKey, partnerKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(rest, ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
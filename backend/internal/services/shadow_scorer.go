@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"backend/internal/models"
+	"backend/internal/tracing"
+)
+
+// ShadowScoreStore persists the scores candidate model versions give articles in shadow
+type ShadowScoreStore interface {
+	// SaveShadowScore stores the score, replacing any earlier score of the article by the same version
+	SaveShadowScore(ctx context.Context, score *models.ShadowScore) error
+	// GetShadowScores returns version's scores from since onwards (all of them if since is zero), oldest first
+	GetShadowScores(ctx context.Context, version string, since time.Time) ([]*models.ShadowScore, error)
+}
+
+// ShadowScorer scores newly saved articles with the registry's shadow version in the background.
+// A candidate never delays a submission: when maxPending predictions are already running or waiting for
+// a worker, further articles are skipped rather than queued.
+type ShadowScorer struct {
+	models *ModelRegistry
+	store  ShadowScoreStore
+	slots  chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewShadowScorer creates a scorer that stores its results in store
+func NewShadowScorer(models *ModelRegistry, store ShadowScoreStore, maxPending int) *ShadowScorer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ShadowScorer{
+		models: models,
+		store:  store,
+		slots:  make(chan struct{}, maxPending),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Score scores the saved article with the shadow version, if there is one, and stores the result next to
+// active, the score the article was saved with. It returns at once; the prediction carries the request ID
+// and trace of ctx but isn't cancelled with it.
+func (s *ShadowScorer) Score(ctx context.Context, articleID string, input models.PredictionInput, active *models.FIREScore) {
+	candidate := s.models.Shadow()
+	if candidate == nil || candidate.Spec.Version == active.ModelVersion {
+		return
+	}
+	version := candidate.Spec.Version
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		metrics.ShadowPredictions.WithLabelValues(version, "skipped").Inc()
+		slog.DebugContext(ctx, "Skipped shadow prediction, too many pending", "article_id", articleID, "model_version", version)
+		return
+	}
+
+	requestID, parent := logging.RequestID(ctx), trace.SpanContextFromContext(ctx)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.slots }()
+
+		ctx := s.ctx
+		if requestID != "" {
+			ctx = logging.WithRequestID(ctx, requestID)
+		}
+		ctx, span := tracing.Tracer().Start(trace.ContextWithSpanContext(ctx, parent), "shadow.score",
+			trace.WithAttributes(attribute.String("article.id", articleID), attribute.String("model.version", version)))
+		err := s.score(ctx, candidate, articleID, input, active)
+		tracing.EndSpan(span, err)
+
+		if err != nil {
+			metrics.ShadowPredictions.WithLabelValues(version, "failed").Inc()
+			slog.WarnContext(ctx, "Shadow prediction failed", "article_id", articleID, "model_version", version, "error", err)
+			return
+		}
+		metrics.ShadowPredictions.WithLabelValues(version, "stored").Inc()
+	}()
+}
+
+func (s *ShadowScorer) score(ctx context.Context, candidate *RegisteredModel, articleID string, input models.PredictionInput, active *models.FIREScore) error {
+	fireScore, err := candidate.ML.PredictFIREScore(ctx, input)
+	if err != nil {
+		return err
+	}
+	shadow := &models.ShadowScore{
+		ArticleID:     articleID,
+		ModelVersion:  fireScore.ModelVersion,
+		Score:         fireScore.OverallScore,
+		Confidence:    fireScore.Confidence,
+		ActiveVersion: active.ModelVersion,
+		ActiveScore:   active.OverallScore,
+		ScoredAt:      time.Now().UTC(),
+	}
+	if err := s.store.SaveShadowScore(ctx, shadow); err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Stored shadow score", "article_id", articleID, "model_version", shadow.ModelVersion,
+		"score", shadow.Score, "active_score", shadow.ActiveScore)
+	return nil
+}
+
+// Shutdown stops accepting articles and waits for pending predictions to finish.
+// If ctx ends first they are cancelled and Shutdown returns ctx's error once they have stopped.
+func (s *ShadowScorer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,768 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"backend/internal/models"
+)
+
+// SQLStore is an ArticleStore backed by SQLite (embedded, the default) or PostgreSQL
+type SQLStore struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+// sqlDialect captures the differences between the supported databases
+type sqlDialect struct {
+	name          string
+	driver        string
+	timestampType string
+	// numberedParams is true when the driver expects $1, $2... instead of ?
+	numberedParams bool
+	// forUpdate locks selected rows for the rest of a transaction; SQLite already serializes writers
+	forUpdate string
+}
+
+var (
+	sqliteDialect   = sqlDialect{name: "sqlite", driver: "sqlite", timestampType: "TIMESTAMP"}
+	postgresDialect = sqlDialect{name: "postgres", driver: "pgx", timestampType: "TIMESTAMPTZ", numberedParams: true, forUpdate: " FOR UPDATE"}
+)
+
+// migrations are applied in order at startup; never edit one that has shipped, append a new one instead
+var migrations = []func(d sqlDialect) []string{
+	// 1: articles
+	func(d sqlDialect) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE articles (
+				id               TEXT PRIMARY KEY,
+				title            TEXT NOT NULL,
+				content          TEXT NOT NULL,
+				url              TEXT NOT NULL DEFAULT '',
+				source           TEXT NOT NULL,
+				author           TEXT NOT NULL DEFAULT '',
+				published_at     %[1]s NOT NULL,
+				submitted_at     %[1]s NOT NULL,
+				fire_score       INTEGER NOT NULL,
+				model_version    TEXT NOT NULL,
+				needs_moderation BOOLEAN NOT NULL DEFAULT FALSE
+			)`, d.timestampType),
+			`CREATE INDEX idx_articles_submitted_at ON articles (submitted_at)`,
+			`CREATE INDEX idx_articles_moderation ON articles (needs_moderation, fire_score)`,
+		}
+	},
+	// 2: indexes for the article list's filters and sort orders
+	func(d sqlDialect) []string {
+		return []string{
+			`CREATE INDEX idx_articles_published_at ON articles (published_at, id)`,
+			`CREATE INDEX idx_articles_fire_score ON articles (fire_score, id)`,
+			`CREATE INDEX idx_articles_source ON articles (source, submitted_at)`,
+		}
+	},
+	// 3: individual report records and per-article report aggregates
+	func(d sqlDialect) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE reports (
+				id          TEXT PRIMARY KEY,
+				article_id  TEXT NOT NULL REFERENCES articles (id),
+				reporter_id TEXT NOT NULL,
+				category    TEXT NOT NULL,
+				reason      TEXT NOT NULL DEFAULT '',
+				created_at  %[1]s NOT NULL
+			)`, d.timestampType),
+			`CREATE UNIQUE INDEX idx_reports_article_reporter ON reports (article_id, reporter_id)`,
+			`ALTER TABLE articles ADD COLUMN report_count INTEGER NOT NULL DEFAULT 0`,
+			fmt.Sprintf(`ALTER TABLE articles ADD COLUMN latest_report_at %s`, d.timestampType),
+		}
+	},
+	// 4: moderator override log; model_score keeps the model's original score once fire_score is overridden
+	func(d sqlDialect) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE overrides (
+				id             TEXT PRIMARY KEY,
+				article_id     TEXT NOT NULL REFERENCES articles (id),
+				previous_score INTEGER NOT NULL,
+				new_score      INTEGER NOT NULL,
+				new_label      TEXT NOT NULL,
+				confidence     DOUBLE PRECISION NOT NULL,
+				moderator_id   TEXT NOT NULL,
+				notes          TEXT NOT NULL DEFAULT '',
+				created_at     %[1]s NOT NULL
+			)`, d.timestampType),
+			`CREATE INDEX idx_overrides_article_id ON overrides (article_id, created_at)`,
+			`ALTER TABLE articles ADD COLUMN model_score INTEGER NOT NULL DEFAULT 0`,
+			`UPDATE articles SET model_score = fire_score`,
+		}
+	},
+	// 5: partner API keys, their daily usage, and the key each article was submitted with
+	func(d sqlDialect) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE partner_keys (
+				id                  TEXT PRIMARY KEY,
+				partner_name        TEXT NOT NULL,
+				allowed_sources     TEXT NOT NULL DEFAULT '[]',
+				daily_request_quota INTEGER NOT NULL DEFAULT 0,
+				daily_article_quota INTEGER NOT NULL DEFAULT 0,
+				secret_hash         TEXT NOT NULL,
+				created_at          %[1]s NOT NULL,
+				rotated_at          %[1]s,
+				revoked_at          %[1]s
+			)`, d.timestampType),
+			`CREATE TABLE partner_usage (
+				key_id   TEXT NOT NULL REFERENCES partner_keys (id),
+				day      TEXT NOT NULL,
+				requests INTEGER NOT NULL DEFAULT 0,
+				articles INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (key_id, day)
+			)`,
+			`ALTER TABLE articles ADD COLUMN partner_key_id TEXT NOT NULL DEFAULT ''`,
+		}
+	},
+	// 6: the model's separate headline and body scores; NULL for articles scored before they existed
+	func(d sqlDialect) []string {
+		return []string{
+			`ALTER TABLE articles ADD COLUMN headline_score INTEGER`,
+			`ALTER TABLE articles ADD COLUMN body_score INTEGER`,
+		}
+	},
+	// 7: explanations of article scores, stored as JSON once computed
+	func(d sqlDialect) []string {
+		return []string{
+			`ALTER TABLE articles ADD COLUMN explanation TEXT`,
+		}
+	},
+	// 8: candidate model scores made in shadow, next to the active model's score at the time
+	func(d sqlDialect) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE shadow_scores (
+				article_id     TEXT NOT NULL REFERENCES articles (id),
+				model_version  TEXT NOT NULL,
+				score          INTEGER NOT NULL,
+				confidence     DOUBLE PRECISION NOT NULL,
+				active_version TEXT NOT NULL,
+				active_score   INTEGER NOT NULL,
+				scored_at      %[1]s NOT NULL,
+				PRIMARY KEY (model_version, article_id)
+			)`, d.timestampType),
+			`CREATE INDEX idx_shadow_scores_scored_at ON shadow_scores (model_version, scored_at)`,
+		}
+	},
+}
+
+// NewSQLStore opens the database described by dsn and applies any pending migrations.
+// postgres:// and postgresql:// DSNs use PostgreSQL; anything else is treated as a SQLite file path.
+func NewSQLStore(dsn string) (*SQLStore, error) {
+	dialect := sqliteDialect
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		dialect = postgresDialect
+	} else {
+		if dsn == "" {
+			dsn = "fire.db"
+		}
+		if !strings.Contains(dsn, "_pragma") {
+			sep := "?"
+			if strings.Contains(dsn, "?") {
+				sep = "&"
+			}
+			dsn += sep + "_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+		}
+	}
+
+	db, err := sql.Open(dialect.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if dialect == sqliteDialect {
+		// SQLite allows a single writer; serialising through one connection avoids SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", dialect.name, err)
+	}
+
+	s := &SQLStore{db: db, dialect: dialect}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate %s schema: %w", dialect.name, err)
+	}
+	return s, nil
+}
+
+// Close closes the underlying database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// Ping checks that the database accepts connections
+func (s *SQLStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// rebind rewrites ? placeholders to $1, $2... for drivers that need numbered parameters
+func (s *SQLStore) rebind(query string) string {
+	if !s.dialect.numberedParams {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// migrate applies every migration newer than the recorded schema version, each in its own transaction
+func (s *SQLStore) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at %s NOT NULL
+	)`, s.dialect.timestampType))
+	if err != nil {
+		return err
+	}
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range migrations[i](s.dialect) {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d: %w", version, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), version, time.Now().UTC()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "Applied migration", "dialect", s.dialect.name, "version", version)
+	}
+	return nil
+}
+
+const articleColumns = `id, title, content, url, source, author, published_at, submitted_at, fire_score, model_version,
+	report_count, latest_report_at, model_score, partner_key_id, headline_score, body_score`
+
+// scanArticle reads a row selected with articleColumns
+func scanArticle(row interface{ Scan(...interface{}) error }) (*models.Article, error) {
+	var article models.Article
+	var fireScore int
+	var latestReportAt sql.NullTime
+	var headlineScore, bodyScore sql.NullInt64
+	err := row.Scan(&article.ID, &article.Title, &article.Content, &article.URL, &article.Source, &article.Author,
+		&article.PublishedAt, &article.SubmittedAt, &fireScore, &article.ModelVersion,
+		&article.ReportCount, &latestReportAt, &article.ModelScore, &article.PartnerKeyID, &headlineScore, &bodyScore)
+	if err != nil {
+		return nil, err
+	}
+	article.LatestReportAt = latestReportAt.Time
+	article.HeadlineScore = nullableInt(headlineScore)
+	article.BodyScore = nullableInt(bodyScore)
+	article.FIREScore = &models.FIREScore{
+		OverallScore: fireScore,
+		Timestamp:    article.SubmittedAt,
+		ModelVersion: article.ModelVersion,
+	}
+	return &article, nil
+}
+
+// nullableInt converts a nullable integer column to nil or a pointer to its value
+func nullableInt(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	val := int(n.Int64)
+	return &val
+}
+
+func (s *SQLStore) queryArticles(ctx context.Context, query string, args ...interface{}) ([]*models.Article, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []*models.Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
+
+func (s *SQLStore) SaveArticle(ctx context.Context, article *models.Article) (string, error) {
+	id := newDocumentID()
+	fireScore := 0
+	modelVersion := ""
+	var headlineScore, bodyScore *int
+	if article.FIREScore != nil {
+		fireScore, modelVersion = article.FIREScore.OverallScore, article.FIREScore.ModelVersion
+		headlineScore, bodyScore = article.FIREScore.HeadlineScore, article.FIREScore.BodyScore
+	}
+
+	_, err := s.db.ExecContext(ctx, s.rebind(`INSERT INTO articles
+		(id, title, content, url, source, author, published_at, submitted_at, fire_score, model_score, model_version,
+		needs_moderation, partner_key_id, headline_score, body_score)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		id, article.Title, article.Content, article.URL, article.Source, article.Author,
+		article.PublishedAt.UTC(), time.Now().UTC(), fireScore, fireScore, modelVersion, false, article.PartnerKeyID,
+		headlineScore, bodyScore)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// SaveArticles inserts each article independently so one bad row doesn't fail the batch
+func (s *SQLStore) SaveArticles(ctx context.Context, articles []*models.Article) []SaveResult {
+	results := make([]SaveResult, len(articles))
+	for i, article := range articles {
+		results[i].ID, results[i].Err = s.SaveArticle(ctx, article)
+	}
+	return results
+}
+
+// GetArticles returns one page of articles matching the query
+func (s *SQLStore) GetArticles(ctx context.Context, query models.ArticleQuery) (*models.ArticlePage, error) {
+	return s.page(ctx, normalizeQuery(query), nil, nil)
+}
+
+// page returns one page of articles matching the query's filters plus any extra where clauses,
+// using keyset pagination on (sort column, id)
+func (s *SQLStore) page(ctx context.Context, query models.ArticleQuery, where []string, args []interface{}) (*models.ArticlePage, error) {
+	add := func(clause string, values ...interface{}) {
+		where = append(where, clause)
+		args = append(args, values...)
+	}
+	if query.Source != "" {
+		add("source = ?", query.Source)
+	}
+	if query.Author != "" {
+		add("author = ?", query.Author)
+	}
+	if query.ModelVersion != "" {
+		add("model_version = ?", query.ModelVersion)
+	}
+	if query.MinScore != nil {
+		add("fire_score >= ?", *query.MinScore)
+	}
+	if query.MaxScore != nil {
+		add("fire_score <= ?", *query.MaxScore)
+	}
+	if !query.PublishedFrom.IsZero() {
+		add("published_at >= ?", query.PublishedFrom.UTC())
+	}
+	if !query.PublishedTo.IsZero() {
+		add("published_at < ?", query.PublishedTo.UTC())
+	}
+	if !query.SubmittedFrom.IsZero() {
+		add("submitted_at >= ?", query.SubmittedFrom.UTC())
+	}
+	if !query.SubmittedTo.IsZero() {
+		add("submitted_at < ?", query.SubmittedTo.UTC())
+	}
+
+	// query.SortBy has been normalized to a known column name, so it is safe to interpolate
+	direction, op := "ASC", ">"
+	if query.Descending {
+		direction, op = "DESC", "<"
+	}
+	if query.PageToken != "" {
+		cursor, err := decodePageToken(query.PageToken, query.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		value, _ := cursor.typedValue()
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC()
+		}
+		add(fmt.Sprintf("(%s, id) %s (?, ?)", query.SortBy, op), value, cursor.ID)
+	}
+
+	sqlQuery := `SELECT ` + articleColumns + ` FROM articles`
+	if len(where) > 0 {
+		sqlQuery += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	sqlQuery += fmt.Sprintf(` ORDER BY %[1]s %[2]s, id %[2]s LIMIT ?`, query.SortBy, direction)
+	args = append(args, query.PageSize+1)
+
+	articles, err := s.queryArticles(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ArticlePage{Articles: articles}
+	if len(articles) > query.PageSize {
+		page.Articles = articles[:query.PageSize]
+		page.NextPageToken = encodePageToken(page.Articles[query.PageSize-1], query.SortBy)
+	}
+	return page, nil
+}
+
+func (s *SQLStore) GetArticleByID(ctx context.Context, id string) (*models.Article, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(`SELECT `+articleColumns+` FROM articles WHERE id = ?`), id)
+	article, err := scanArticle(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrArticleNotFound
+	}
+	return article, err
+}
+
+// ReportArticle records the report, updates the article's report aggregates and flags it for moderation.
+// The unique (article_id, reporter_id) index makes a repeat report a no-op that returns ErrDuplicateReport.
+func (s *SQLStore) ReportArticle(ctx context.Context, report *models.Report) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, s.rebind(`SELECT 1 FROM articles WHERE id = ?`), report.ArticleID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO reports (id, article_id, reporter_id, category, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (article_id, reporter_id) DO NOTHING`),
+		newDocumentID(), report.ArticleID, report.ReporterID, report.Category, report.Reason, report.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrDuplicateReport
+	}
+
+	_, err = tx.ExecContext(ctx, s.rebind(`UPDATE articles
+		SET report_count = report_count + 1, latest_report_at = ?, needs_moderation = ? WHERE id = ?`),
+		report.CreatedAt.UTC(), true, report.ArticleID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "Article marked for moderation", "article_id", report.ArticleID)
+	return nil
+}
+
+// GetReports returns the article's reports, newest first
+func (s *SQLStore) GetReports(ctx context.Context, articleID string) ([]*models.Report, error) {
+	if _, err := s.GetArticleByID(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT id, article_id, reporter_id, category, reason, created_at
+		FROM reports WHERE article_id = ? ORDER BY created_at DESC`), articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*models.Report{}
+	for rows.Next() {
+		var report models.Report
+		if err := rows.Scan(&report.ID, &report.ArticleID, &report.ReporterID, &report.Category, &report.Reason, &report.CreatedAt); err != nil {
+			return nil, err
+		}
+		reports = append(reports, &report)
+	}
+	return reports, rows.Err()
+}
+
+// ApplyModeratorOverride updates an article with moderator's override and appends it to the overrides table
+// in the same transaction, so the log always matches the article's effective score
+func (s *SQLStore) ApplyModeratorOverride(ctx context.Context, override *models.Override) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousScore int
+	err = tx.QueryRowContext(ctx, s.rebind(`SELECT fire_score FROM articles WHERE id = ?`+s.dialect.forUpdate), override.ArticleID).
+		Scan(&previousScore)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	id := newDocumentID()
+	_, err = tx.ExecContext(ctx, s.rebind(`INSERT INTO overrides
+		(id, article_id, previous_score, new_score, new_label, confidence, moderator_id, notes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		id, override.ArticleID, previousScore, override.NewScore, override.NewLabel, override.Confidence,
+		override.ModeratorID, override.Notes, override.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, s.rebind(`UPDATE articles SET fire_score = ?, needs_moderation = ? WHERE id = ?`),
+		override.NewScore, false, override.ArticleID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	override.ID = id
+	override.PreviousScore = previousScore
+
+	slog.DebugContext(ctx, "Stored moderator override", "article_id", override.ArticleID, "new_score", override.NewScore)
+	return nil
+}
+
+// SaveExplanation stores the explanation as JSON in the article's row
+func (s *SQLStore) SaveExplanation(ctx context.Context, articleID string, explanation *models.Explanation) error {
+	encoded, err := json.Marshal(explanation)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(ctx, s.rebind(`UPDATE articles SET explanation = ? WHERE id = ?`), string(encoded), articleID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrArticleNotFound
+	}
+	return nil
+}
+
+// GetExplanation returns the article's explanation, or nil if none has been stored
+func (s *SQLStore) GetExplanation(ctx context.Context, articleID string) (*models.Explanation, error) {
+	var encoded sql.NullString
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT explanation FROM articles WHERE id = ?`), articleID).Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil || !encoded.Valid {
+		return nil, err
+	}
+	var explanation models.Explanation
+	if err := json.Unmarshal([]byte(encoded.String), &explanation); err != nil {
+		return nil, fmt.Errorf("article %s: invalid explanation: %w", articleID, err)
+	}
+	return &explanation, nil
+}
+
+// GetOverrideHistory returns the article's overrides, oldest first
+func (s *SQLStore) GetOverrideHistory(ctx context.Context, articleID string) ([]*models.Override, error) {
+	if _, err := s.GetArticleByID(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT id, article_id, previous_score, new_score, new_label, confidence,
+		moderator_id, notes, created_at FROM overrides WHERE article_id = ? ORDER BY created_at, id`), articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []*models.Override{}
+	for rows.Next() {
+		var o models.Override
+		err := rows.Scan(&o.ID, &o.ArticleID, &o.PreviousScore, &o.NewScore, &o.NewLabel, &o.Confidence,
+			&o.ModeratorID, &o.Notes, &o.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, &o)
+	}
+	return overrides, rows.Err()
+}
+
+// GetModeratorQueue retrieves a page of articles that need moderation, sorted by fire_score ascending (lowest/worst first)
+func (s *SQLStore) GetModeratorQueue(ctx context.Context, pageSize int, pageToken string) (*models.ArticlePage, error) {
+	query := normalizeQuery(models.ArticleQuery{PageSize: pageSize, PageToken: pageToken, SortBy: models.SortByFIREScore})
+	page, err := s.page(ctx, query, []string{"needs_moderation = ?"}, []interface{}{true})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// CountModerationQueue returns how many articles are flagged for moderation
+func (s *SQLStore) CountModerationQueue(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, s.rebind("SELECT COUNT(*) FROM articles WHERE needs_moderation = ?"), true).Scan(&n)
+	return n, err
+}
+
+const partnerKeyColumns = `id, partner_name, allowed_sources, daily_request_quota, daily_article_quota, secret_hash,
+	created_at, rotated_at, revoked_at`
+
+// scanPartnerKey reads a row selected with partnerKeyColumns
+func scanPartnerKey(row interface{ Scan(...interface{}) error }) (*models.PartnerKey, error) {
+	var key models.PartnerKey
+	var allowedSources string
+	var rotatedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.PartnerName, &allowedSources, &key.DailyRequestQuota, &key.DailyArticleQuota,
+		&key.SecretHash, &key.CreatedAt, &rotatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(allowedSources), &key.AllowedSources); err != nil {
+		return nil, fmt.Errorf("partner key %s: invalid allowed_sources: %w", key.ID, err)
+	}
+	if rotatedAt.Valid {
+		key.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
+
+// nullableTime converts an optional time for a nullable timestamp column
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// SavePartnerKey creates or replaces a partner key
+func (s *SQLStore) SavePartnerKey(ctx context.Context, key *models.PartnerKey) error {
+	allowedSources, err := json.Marshal(append([]string{}, key.AllowedSources...))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(`INSERT INTO partner_keys (`+partnerKeyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			partner_name = excluded.partner_name, allowed_sources = excluded.allowed_sources,
+			daily_request_quota = excluded.daily_request_quota, daily_article_quota = excluded.daily_article_quota,
+			secret_hash = excluded.secret_hash, rotated_at = excluded.rotated_at, revoked_at = excluded.revoked_at`),
+		key.ID, key.PartnerName, string(allowedSources), key.DailyRequestQuota, key.DailyArticleQuota, key.SecretHash,
+		key.CreatedAt.UTC(), nullableTime(key.RotatedAt), nullableTime(key.RevokedAt))
+	return err
+}
+
+// GetPartnerKey returns the partner key with the given ID
+func (s *SQLStore) GetPartnerKey(ctx context.Context, id string) (*models.PartnerKey, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(`SELECT `+partnerKeyColumns+` FROM partner_keys WHERE id = ?`), id)
+	key, err := scanPartnerKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPartnerKeyNotFound
+	}
+	return key, err
+}
+
+// ListPartnerKeys returns every partner key, oldest first
+func (s *SQLStore) ListPartnerKeys(ctx context.Context) ([]*models.PartnerKey, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+partnerKeyColumns+` FROM partner_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.PartnerKey{}
+	for rows.Next() {
+		key, err := scanPartnerKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// AddPartnerUsage adds to the key's counters for day in a single upsert and returns the new totals
+func (s *SQLStore) AddPartnerUsage(ctx context.Context, keyID, day string, requests, articles int) (*models.PartnerUsage, error) {
+	usage := models.PartnerUsage{KeyID: keyID, Day: day}
+	err := s.db.QueryRowContext(ctx, s.rebind(`INSERT INTO partner_usage (key_id, day, requests, articles) VALUES (?, ?, ?, ?)
+		ON CONFLICT (key_id, day) DO UPDATE SET
+			requests = partner_usage.requests + excluded.requests, articles = partner_usage.articles + excluded.articles
+		RETURNING requests, articles`), keyID, day, requests, articles).Scan(&usage.Requests, &usage.Articles)
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// GetPartnerUsage returns the key's counters for day
+func (s *SQLStore) GetPartnerUsage(ctx context.Context, keyID, day string) (*models.PartnerUsage, error) {
+	usage := models.PartnerUsage{KeyID: keyID, Day: day}
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT requests, articles FROM partner_usage WHERE key_id = ? AND day = ?`),
+		keyID, day).Scan(&usage.Requests, &usage.Articles)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// SaveShadowScore upserts the score, replacing the article's earlier score by the same version
+func (s *SQLStore) SaveShadowScore(ctx context.Context, score *models.ShadowScore) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(`INSERT INTO shadow_scores
+		(article_id, model_version, score, confidence, active_version, active_score, scored_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (model_version, article_id) DO UPDATE SET
+			score = excluded.score, confidence = excluded.confidence, active_version = excluded.active_version,
+			active_score = excluded.active_score, scored_at = excluded.scored_at`),
+		score.ArticleID, score.ModelVersion, score.Score, score.Confidence, score.ActiveVersion, score.ActiveScore,
+		score.ScoredAt.UTC())
+	return err
+}
+
+// GetShadowScores returns version's scores from since onwards, oldest first
+func (s *SQLStore) GetShadowScores(ctx context.Context, version string, since time.Time) ([]*models.ShadowScore, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT article_id, model_version, score, confidence, active_version,
+		active_score, scored_at FROM shadow_scores WHERE model_version = ? AND scored_at >= ? ORDER BY scored_at, article_id`),
+		version, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []*models.ShadowScore{}
+	for rows.Next() {
+		var score models.ShadowScore
+		err := rows.Scan(&score.ArticleID, &score.ModelVersion, &score.Score, &score.Confidence, &score.ActiveVersion,
+			&score.ActiveScore, &score.ScoredAt)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, &score)
+	}
+	return scores, rows.Err()
+}
@@ -1,99 +1,714 @@
 package main
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 
+	"backend/internal/auth"
+	"backend/internal/config"
 	"backend/internal/handlers"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"backend/internal/ratelimit"
 	"backend/internal/services"
+	"backend/internal/tracing"
 )
 
 func main() {
-	log.Println("🚀 FIRE News Backend Starting...")
+	logging.Setup(os.Stderr)
+	slog.Info("FIRE News backend starting")
+
+	// Defaults, then CONFIG_FILE (or ./config.yaml), then environment variables
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		fatal("Failed to load configuration", err)
+	}
+	// Validated by config.Load
+	logging.SetLevel(cfg.Log.Level)
+	slog.Info("Effective configuration", "config", cfg.String())
 
 	// Get paths
-	pythonPath := getPythonPath()
-	scriptPath := getScriptPath()
+	pythonPath := getPythonPath(cfg.ML.PythonPath)
+	scriptPath := getScriptPath(cfg.ML.ScriptPath)
+	registryPath := cfg.ML.Registry
+	if registryPath == "" {
+		registryPath = filepath.Join(filepath.Dir(scriptPath), "models.yaml")
+	}
 
-	log.Printf("Python path: %s", pythonPath)
-	log.Printf("Script path: %s", scriptPath)
+	slog.Info("ML paths", "python_path", pythonPath, "script_path", scriptPath, "registry", registryPath)
 
-	// Initialize ML service
-	mlService := services.NewMLService(pythonPath, scriptPath)
+	manifest, err := services.LoadModelManifest(registryPath)
+	if err != nil {
+		fatal("Failed to load model registry", err)
+	}
 
-	// Initialize Firestore service (no credentials needed with public rules)
-	firestoreService, err := services.NewFirestoreService()
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.OTLPEndpoint,
+		cfg.Tracing.SampleRatio, manifest.Active)
 	if err != nil {
-		log.Fatalf("Failed to initialize Firestore: %v", err)
+		fatal("Failed to initialize tracing", err)
+	}
+
+	// Start a pool of warm Python workers for every model version in the registry
+	modelRegistry := services.NewModelRegistry(manifest, services.MLOptions{
+		PythonPath:         pythonPath,
+		ScriptPath:         scriptPath,
+		Aggregation:        cfg.ML.Aggregation,
+		HeadlineWeight:     cfg.ML.HeadlineWeight,
+		Workers:            cfg.ML.Workers,
+		Timeout:            cfg.ML.Timeout,
+		ExplanationTimeout: cfg.ML.ExplanationTimeout,
+	})
+
+	// Check the model files up front so a missing or unpulled model shows in the startup log,
+	// not just in readiness probes
+	go func() {
+		if err := modelRegistry.VerifyChecksums(context.Background()); err != nil {
+			slog.Warn("Model check failed", "error", err)
+		}
+	}()
+
+	// Initialize article store
+	backend, err := newArticleStore(cfg)
+	if err != nil {
+		fatal("Failed to initialize article store", err)
+	}
+	store := services.NewInstrumentedStore(backend, cfg.Store.Backend)
+	metrics.RegisterModerationQueueDepth(store.CountModerationQueue, queueDepthInterval)
+	partnerKeys := services.NewPartnerKeyService(store)
+
+	// Background queue for ?async=true submissions
+	jobQueue := services.NewJobQueue(cfg.Jobs.Workers, cfg.Jobs.QueueSize, cfg.Jobs.Retention)
+	// Scores new articles with the manifest's shadow version, if any, without showing the results
+	shadowScorer := services.NewShadowScorer(modelRegistry, store, cfg.ML.ShadowMaxPending)
+
+	// Firebase ID token verification and role resolution for protected endpoints
+	verifier, err := newVerifier(cfg)
+	if err != nil {
+		fatal("Failed to initialize auth", err)
+	}
+	roles, err := newRoleResolver(cfg.Auth.RolesFile)
+	if err != nil {
+		fatal("Failed to load roles", err)
+	}
+	limiters, err := newRateLimiters(cfg.RateLimits)
+	if err != nil {
+		fatal("Failed to configure rate limits", err)
+	}
+	trustedProxies, err := handlers.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		fatal("Failed to parse trusted proxies", err)
 	}
 
 	// Initialize handlers
-	articleHandler := handlers.NewArticleHandler(mlService, firestoreService)
+	thresholds := versionThresholds(cfg.Thresholds, manifest)
+	articleHandler := handlers.NewArticleHandler(modelRegistry, shadowScorer, store, jobQueue, partnerKeys, thresholds,
+		cfg.Server.WriteTimeout)
+	jobHandler := handlers.NewJobHandler(jobQueue, thresholds)
+	modelHandler := handlers.NewModelHandler(modelRegistry, store, thresholds)
+	partnerKeyHandler := handlers.NewPartnerKeyHandler(partnerKeys)
+	healthHandler := handlers.NewHealthHandler(cfg.Server.HealthCheckTimeout, modelRegistry,
+		handlers.HealthCheck{Name: "ml", Check: modelRegistry.Ready},
+		handlers.HealthCheck{Name: "store", Check: store.Ping},
+		handlers.HealthCheck{Name: "model", Check: modelRegistry.VerifyChecksums},
+	)
 
 	// Setup router
 	r := mux.NewRouter()
 
 	// API routes
 	api := r.PathPrefix("/api/v1").Subrouter()
-	api.HandleFunc("/partner/submit", articleHandler.SubmitArticle).Methods("POST", "OPTIONS")
-	api.HandleFunc("/articles/{id}/report", articleHandler.ReportArticle).Methods("POST", "OPTIONS")
+	api.HandleFunc("/partner/submit", articleHandler.SubmitArticle).Methods("POST", "OPTIONS").Name("partner.submit")
+	api.HandleFunc("/partner/submit/batch", articleHandler.SubmitArticleBatch).Methods("POST", "OPTIONS").Name("partner.submit_batch")
+	api.HandleFunc("/partner/usage", partnerKeyHandler.GetUsage).Methods("GET", "OPTIONS").Name("partner.usage")
+	api.HandleFunc("/articles/{id}/report", articleHandler.ReportArticle).Methods("POST", "OPTIONS").Name("articles.report")
+	api.HandleFunc("/articles/{id}/history", articleHandler.GetArticleHistory).Methods("GET", "OPTIONS").Name("articles.history")
+	api.HandleFunc("/articles/{id}/explanation", articleHandler.GetArticleExplanation).Methods("GET", "OPTIONS").Name("articles.explanation")
 	api.HandleFunc("/articles/{id}", articleHandler.GetArticleByID).Methods("GET", "OPTIONS")
 	api.HandleFunc("/articles", articleHandler.GetArticles).Methods("GET", "OPTIONS")
-	api.HandleFunc("/moderator/queue", articleHandler.GetModeratorQueue).Methods("GET", "OPTIONS")
-	api.HandleFunc("/moderator/override", articleHandler.OverrideFIREScore).Methods("POST", "OPTIONS")
+	api.HandleFunc("/moderator/queue", articleHandler.GetModeratorQueue).Methods("GET", "OPTIONS").Name("moderator.queue")
+	api.HandleFunc("/moderator/override", articleHandler.OverrideFIREScore).Methods("POST", "OPTIONS").Name("moderator.override")
+	api.HandleFunc("/moderator/articles/{id}/reports", articleHandler.GetArticleReports).Methods("GET", "OPTIONS").Name("moderator.reports")
+	api.HandleFunc("/jobs/{id}", jobHandler.GetJob).Methods("GET", "OPTIONS").Name("jobs.get")
+	api.HandleFunc("/admin/partner-keys", partnerKeyHandler.CreateKey).Methods("POST", "OPTIONS").Name("admin.partner_keys.create")
+	api.HandleFunc("/admin/partner-keys", partnerKeyHandler.ListKeys).Methods("GET", "OPTIONS").Name("admin.partner_keys.list")
+	api.HandleFunc("/admin/partner-keys/{id}/rotate", partnerKeyHandler.RotateKey).Methods("POST", "OPTIONS").Name("admin.partner_keys.rotate")
+	api.HandleFunc("/admin/partner-keys/{id}/sources", partnerKeyHandler.SetSources).Methods("PUT", "OPTIONS").Name("admin.partner_keys.sources")
+	api.HandleFunc("/admin/partner-keys/{id}", partnerKeyHandler.RevokeKey).Methods("DELETE", "OPTIONS").Name("admin.partner_keys.revoke")
+	api.HandleFunc("/admin/log-level", handlers.GetLogLevel).Methods("GET", "OPTIONS").Name("admin.log_level.get")
+	api.HandleFunc("/admin/log-level", handlers.SetLogLevel).Methods("PUT", "OPTIONS").Name("admin.log_level.set")
+	api.HandleFunc("/admin/models", modelHandler.ListModels).Methods("GET", "OPTIONS").Name("admin.models.list")
+	api.HandleFunc("/admin/models/active", modelHandler.ActivateModel).Methods("PUT", "OPTIONS").Name("admin.models.activate")
+	api.HandleFunc("/admin/models/shadow", modelHandler.ShadowReport).Methods("GET", "OPTIONS").Name("admin.models.shadow")
 
-	// Apply CORS middleware
-	r.Use(corsMiddleware)
+	// Routes named in routePermissions require a signed-in user with the listed permission
+	if verifier != nil {
+		api.Use(authMiddleware(verifier, roles, partnerKeys, limiters["auth"]))
+	}
+	// Runs after authentication so per-user and per-key buckets know the caller; failed authentication
+	// is limited per address by the auth limiter instead
+	api.Use(rateLimitMiddleware(limiters))
+
+	// Every route gets a request ID first so CORS, logging and handlers can all use it
+	r.Use(requestIDMiddleware)
+	// Resolve the client address once, so reports, rate limits and logs agree on who the caller is
+	r.Use(trustedProxies.Middleware)
+	r.Use(tracingMiddleware)
+	r.Use(corsMiddleware(cfg.CORSOrigins))
 	r.Use(loggingMiddleware)
+	r.Use(metricsMiddleware)
+
+	// Health checks: live only means the process is serving, ready means every dependency works
+	r.HandleFunc("/health", healthHandler.GetLiveness).Methods("GET")
+	r.HandleFunc("/health/live", healthHandler.GetLiveness).Methods("GET")
+	r.HandleFunc("/health/ready", healthHandler.GetReadiness).Methods("GET")
 
-	// Health check
-	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	}).Methods("GET")
+	// Prometheus scrape endpoint
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
 
 	// Start server
-	port := "8080"
-	log.Printf("✅ Server running on http://localhost:%s", port)
-	log.Printf("✅ API endpoint: http://localhost:%s/api/v1", port)
-	log.Fatal(http.ListenAndServe(":"+port, r))
+	port := strconv.Itoa(cfg.Port)
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+	slog.Info("Server running", "url", "http://localhost:"+port, "api", "http://localhost:"+port+"/api/v1")
+
+	// Wait for SIGINT/SIGTERM; a second signal kills the process without waiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down, signal again to force", "timeout", cfg.Server.ShutdownTimeout.String())
+	case err := <-serverErr:
+		slog.Error("Server failed", "error", err)
+		exitCode = 1
+	}
+	stop()
+
+	shutdown(srv, cfg.Server.ShutdownTimeout, jobQueue, shadowScorer, modelRegistry, store, shutdownTracing)
+	os.Exit(exitCode)
+}
+
+// shutdown drains in-flight requests, then stops background work in dependency order:
+// queued submissions start shadow predictions, both still need the ML workers and the store,
+// so those are closed last, and spans are flushed once nothing can start new ones.
+// The timeout is shared by the HTTP server, the job queue and the shadow scorer.
+func shutdown(srv *http.Server, timeout time.Duration, jobs *services.JobQueue, shadow *services.ShadowScorer, models *services.ModelRegistry, store services.Store,
+	shutdownTracing func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP server did not drain in time, closing remaining connections", "error", err)
+		srv.Close()
+	}
+	if err := jobs.Shutdown(ctx); err != nil {
+		slog.Warn("Job queue shut down with unfinished jobs", "error", err)
+	}
+	if err := shadow.Shutdown(ctx); err != nil {
+		slog.Warn("Shadow scorer shut down with unfinished predictions", "error", err)
+	}
+	models.Close()
+	if closer, ok := store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			slog.Error("Failed to close article store", "error", err)
+		}
+	}
+	// Flushing gets its own deadline, as the shared one may already have passed
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+	slog.Info("Shutdown complete")
+}
+
+// newArticleStore returns the store selected by store.backend (firestore, sql or memory)
+func newArticleStore(cfg *config.Config) (services.Store, error) {
+	switch cfg.Store.Backend {
+	case "firestore":
+		// No credentials needed with public rules
+		slog.Info("Using Firestore article store")
+		return services.NewFirestoreService(cfg.FirebaseProjectID)
+	case "sql":
+		// store.database_url is a postgres:// DSN or a SQLite file path
+		store, err := services.NewSQLStore(cfg.Store.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Using SQL article store")
+		return store, nil
+	case "memory":
+		slog.Info("Using in-memory article store (data is not persisted)")
+		return services.NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown store backend %q (expected firestore, sql or memory)", cfg.Store.Backend)
+	}
 }
 
-// corsMiddleware adds CORS headers for React frontend
-func corsMiddleware(next http.Handler) http.Handler {
+// newVerifier builds the ID token verifier for the configured Firebase project.
+// Keys come from auth.jwks_file when set (a local key set for tests), otherwise from auth.jwks_url
+// (Google's Firebase key set by default). auth.disabled turns verification off for local development.
+func newVerifier(cfg *config.Config) (*auth.Verifier, error) {
+	if cfg.Auth.Disabled {
+		slog.Warn("AUTH_DISABLED=true: moderator endpoints are unauthenticated")
+		return nil, nil
+	}
+
+	var keys auth.KeySource
+	if path := cfg.Auth.JWKSFile; path != "" {
+		fileKeys, err := auth.LoadKeySetFile(path)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Verifying ID tokens with keys from file", "keys", len(fileKeys), "path", path)
+		keys = fileKeys
+	} else {
+		url := cfg.Auth.JWKSURL
+		if url == "" {
+			url = auth.FirebaseJWKSURL
+		}
+		slog.Info("Verifying ID tokens with keys from URL", "url", url)
+		keys = auth.NewHTTPKeySource(url)
+	}
+	return auth.NewFirebaseVerifier(cfg.FirebaseProjectID, keys), nil
+}
+
+// newRoleResolver resolves roles from token claims plus the optional roles file at path,
+// a JSON object mapping Firebase UIDs or verified email addresses to role lists
+func newRoleResolver(path string) (*auth.RoleResolver, error) {
+	if path == "" {
+		return auth.NewRoleResolver(nil), nil
+	}
+	store, err := auth.LoadRoleFile(path)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Loaded role assignments", "users", len(store), "path", path)
+	return auth.NewRoleResolver(store), nil
+}
+
+// routePermissions maps route names to the permission needed to call them.
+// Routes that are unnamed or missing from the table are public.
+var routePermissions = map[string]auth.Permission{
+	"partner.submit":       auth.PermSubmitArticles,
+	"partner.submit_batch": auth.PermSubmitArticles,
+	"partner.usage":        auth.PermSubmitArticles,
+	"jobs.get":             auth.PermSubmitArticles,
+	"moderator.queue":      auth.PermReviewQueue,
+	"articles.history":     auth.PermReviewQueue,
+	"articles.explanation": auth.PermReviewQueue,
+	"moderator.reports":    auth.PermReviewQueue,
+	"moderator.override":   auth.PermOverrideScores,
+
+	"admin.partner_keys.create":  auth.PermManageUsers,
+	"admin.partner_keys.list":    auth.PermManageUsers,
+	"admin.partner_keys.rotate":  auth.PermManageUsers,
+	"admin.partner_keys.revoke":  auth.PermManageUsers,
+	"admin.partner_keys.sources": auth.PermManageSources,
+	"admin.log_level.get":        auth.PermManageLogging,
+	"admin.log_level.set":        auth.PermManageLogging,
+	"admin.models.list":          auth.PermManageModels,
+	"admin.models.activate":      auth.PermManageModels,
+	"admin.models.shadow":        auth.PermManageModels,
+}
+
+// optionalAuthRoutes are public routes that still identify callers who send credentials, so that for example
+// a signed-in user's reports are deduplicated by account rather than by address
+var optionalAuthRoutes = map[string]bool{
+	"articles.report": true,
+}
+
+// authMiddleware enforces routePermissions. Protected routes need a partner API key in X-API-Key or
+// a valid Firebase ID token in the Authorization header (401 otherwise) whose roles grant the route's
+// permission (403 otherwise). API key requests count against the key's daily request quota (429 once used up).
+// The resulting principal is stored in the request context. On optionalAuthRoutes credentials may be left out,
+// but any that are sent must be valid. Each rejected API key or token takes a token from the client address's
+// bucket in failures (nil for no limit); once it is empty the address gets 429 without its credentials being checked.
+func authMiddleware(verifier *auth.Verifier, roles *auth.RoleResolver, partnerKeys *services.PartnerKeyService, failures *ratelimit.Limiter) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := mux.CurrentRoute(r).GetName()
+			perm, protected := routePermissions[name]
+			// Preflight requests never carry credentials
+			if r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !protected {
+				if optionalAuthRoutes[name] && hasCredentials(r) {
+					principal, ok := authenticateLimited(w, r, verifier, roles, partnerKeys, failures)
+					if !ok {
+						return
+					}
+					r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, ok := authenticateLimited(w, r, verifier, roles, partnerKeys, failures)
+			if !ok {
+				return
+			}
+			if !principal.Can(perm) {
+				slog.InfoContext(r.Context(), "Access denied", "principal", principal.Name(), "roles", principal.Roles, "required_permission", perm)
+				reason := "missing_permission"
+				if len(principal.Roles) == 0 {
+					reason = "no_role"
+				}
+				writeForbidden(w, reason, perm)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// hasCredentials reports whether the request carries an API key or an Authorization header
+func hasCredentials(r *http.Request) bool {
+	return r.Header.Get("X-API-Key") != "" || r.Header.Get("Authorization") != ""
+}
+
+// authenticateLimited is authenticate behind the failures limiter, so guessing API keys or tokens is
+// throttled per client address before each guess costs a key lookup or a signature check
+func authenticateLimited(w http.ResponseWriter, r *http.Request, verifier *auth.Verifier, roles *auth.RoleResolver,
+	partnerKeys *services.PartnerKeyService, failures *ratelimit.Limiter) (*auth.Principal, bool) {
+	if failures == nil || !hasCredentials(r) {
+		return authenticate(w, r, verifier, roles, partnerKeys)
+	}
+
+	key := rateLimitKey(r, ratelimit.KeyIP)
+	if wait := failures.Wait(key); wait > 0 {
+		slog.InfoContext(r.Context(), "Rate limited after failed authentication", "key", key)
+		writeRateLimited(w, wait)
+		return nil, false
+	}
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	principal, ok := authenticate(rec, r, verifier, roles, partnerKeys)
+	if !ok && rec.status == http.StatusUnauthorized {
+		failures.Allow(key)
+	}
+	return principal, ok
+}
+
+// authenticate identifies the caller from an X-API-Key or a Firebase ID token, writing an error response
+// and returning false if neither is valid
+func authenticate(w http.ResponseWriter, r *http.Request, verifier *auth.Verifier, roles *auth.RoleResolver, partnerKeys *services.PartnerKeyService) (*auth.Principal, bool) {
+	if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+		key, err := partnerKeys.Authenticate(r.Context(), apiKey)
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			slog.InfoContext(r.Context(), "Rejected API key")
+			http.Error(w, "Invalid API key", http.StatusUnauthorized)
+			return nil, false
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to check API key", "error", err)
+			http.Error(w, "Unable to verify API key", http.StatusServiceUnavailable)
+			return nil, false
+		}
+
+		err = partnerKeys.CountRequest(r.Context(), key)
+		var quotaErr *services.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			slog.InfoContext(r.Context(), "Partner key over quota", "partner_key_id", key.ID, "error", err)
+			handlers.WriteQuotaExceeded(w, quotaErr)
+			return nil, false
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to count partner key request", "partner_key_id", key.ID, "error", err)
+			http.Error(w, "Unable to verify API key", http.StatusServiceUnavailable)
+			return nil, false
+		}
+		return auth.PartnerPrincipal(key), true
+	}
+
+	raw, err := auth.BearerToken(r.Header.Get("Authorization"))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+	token, err := verifier.Verify(r.Context(), raw)
+	if errors.Is(err, auth.ErrInvalidToken) {
+		slog.InfoContext(r.Context(), "Rejected ID token", "error", err)
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+		return nil, false
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to verify ID token", "error", err)
+		http.Error(w, "Unable to verify token", http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	principal, err := roles.Principal(r.Context(), token)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to resolve roles", "uid", token.UID, "error", err)
+		http.Error(w, "Unable to resolve roles", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return principal, true
+}
+
+// writeForbidden sends a 403 whose reason is one of no_role (the user has no roles at all)
+// or missing_permission (none of their roles grants the permission)
+func writeForbidden(w http.ResponseWriter, reason string, perm auth.Permission) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":               "forbidden",
+		"reason":              reason,
+		"required_permission": perm,
+	})
+}
+
+// rateLimitGroups assigns route names to rate limit groups; other API routes use the default group
+var rateLimitGroups = map[string]string{
+	"partner.submit":       "submit",
+	"partner.submit_batch": "submit",
+	"articles.report":      "report",
+}
+
+// newRateLimiters builds a limiter per group from the configured rules (see ratelimit.ParseRule).
+// A rule of off disables limiting for the group.
+func newRateLimiters(rules map[string]string) (map[string]*ratelimit.Limiter, error) {
+	limiters := make(map[string]*ratelimit.Limiter, len(rules))
+	for group, value := range rules {
+		if value == "off" {
+			slog.Info("Rate limiting disabled", "group", group)
+			continue
+		}
+		rule, err := ratelimit.ParseRule(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", group, err)
+		}
+		slog.Info("Rate limiting routes", "group", group, "rule", rule.String())
+		limiters[group] = ratelimit.NewLimiter(rule)
+	}
+	return limiters, nil
+}
+
+// rateLimitMiddleware applies the token bucket of the route's group, answering 429 with Retry-After
+// once the caller's bucket is empty
+func rateLimitMiddleware(limiters map[string]*ratelimit.Limiter) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			group, ok := rateLimitGroups[mux.CurrentRoute(r).GetName()]
+			if !ok {
+				group = "default"
+			}
+			limiter := limiters[group]
+			if limiter == nil || r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := rateLimitKey(r, limiter.Rule().Key)
+			if allowed, wait := limiter.Allow(key); !allowed {
+				slog.InfoContext(r.Context(), "Rate limited", "key", key, "group", group)
+				writeRateLimited(w, wait)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeRateLimited sends a 429 telling the caller to retry after wait
+func writeRateLimited(w http.ResponseWriter, wait time.Duration) {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":       "rate_limited",
+		"retry_after": retryAfter,
+	})
+}
+
+// rateLimitKey identifies the caller's bucket, falling back to the client address when the request
+// doesn't carry the identity the rule is keyed by. The address is the one resolved against the trusted
+// proxies, so a spoofed X-Forwarded-For can't move a caller to a new bucket.
+func rateLimitKey(r *http.Request, by ratelimit.Key) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		if principal.PartnerKey != nil && (by == ratelimit.KeyAPIKey || by == ratelimit.KeyUser) {
+			return "api_key:" + principal.PartnerKey.ID
+		}
+		if by == ratelimit.KeyUser {
+			return "user:" + principal.Subject
+		}
+	}
+	return "ip:" + handlers.ClientIP(r)
+}
+
+// corsMiddleware adds CORS headers for the React frontend when the request's Origin is one of origins
+// ("*" allows any origin)
+func corsMiddleware(origins []string) mux.MiddlewareFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); origin != "" && (allowed[origin] || allowed["*"]) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, traceparent, tracestate")
+				w.Header().Set("Access-Control-Expose-Headers", "X-Next-Page-Token, Retry-After, X-Request-ID")
+				w.Header().Set("Access-Control-Max-Age", "3600")
+			}
+
+			// Handle preflight
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestIDMiddleware takes the request ID from X-Request-ID, or generates one if it is missing or malformed,
+// echoes it in the response and stores it in the request context so every log line for the request carries it
+func requestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Max-Age", "3600")
+		id := r.Header.Get("X-Request-ID")
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
 
-		// Handle preflight
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// tracingMiddleware starts a server span per request, named after the route template and continuing the
+// caller's trace when the request carries a traceparent header
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, err := mux.CurrentRoute(r).GetPathTemplate()
+		if err != nil {
+			route = "unknown"
 		}
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Tracer().Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(r.URL.Path),
+				attribute.String("request_id", logging.RequestID(ctx)),
+			))
+		defer span.End()
 
-		next.ServeHTTP(w, r)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
 	})
 }
 
-// loggingMiddleware logs all requests
+// loggingMiddleware logs every request once it has been handled, with its status and duration
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s", r.Method, r.URL.Path)
-		next.ServeHTTP(w, r)
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		level := slog.LevelInfo
+		if rec.status >= 500 {
+			level = slog.LevelError
+		}
+		slog.Log(r.Context(), level, "Request handled",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote_ip", handlers.ClientIP(r),
+		)
+	})
+}
+
+// queueDepthInterval is how stale the exported moderation queue depth may get, so frequent scrapes
+// don't each count the queue in the database
+const queueDepthInterval = 30 * time.Second
+
+// statusRecorder captures the status code a handler writes
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// metricsMiddleware counts and times requests by route template, so /articles/{id} is one series
+// rather than one per article
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, err := mux.CurrentRoute(r).GetPathTemplate()
+		if err != nil {
+			route = "unknown"
+		}
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		labels := []string{route, r.Method, strconv.Itoa(rec.status)}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.ObserveSince(metrics.HTTPRequestDuration.WithLabelValues(labels...), start)
 	})
 }
 
-// getPythonPath returns the Python executable path
-func getPythonPath() string {
-	if path := os.Getenv("PYTHON_PATH"); path != "" {
-		return path
+// fatal logs err and exits; used for startup failures
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// getPythonPath returns the configured Python executable, or the first one found on PATH
+func getPythonPath(configured string) string {
+	if configured != "" {
+		return configured
 	}
 
 	// Try common Python executables in order of preference
@@ -111,12 +726,30 @@ func getPythonPath() string {
 	return "python3"
 }
 
-// getScriptPath returns the absolute path to predict.py
-func getScriptPath() string {
+// versionThresholds uses each model version's thresholds from the manifest, falling back to the configured ones
+func versionThresholds(configured config.ThresholdsConfig, manifest *services.ModelManifest) handlers.VersionThresholds {
+	thresholds := handlers.VersionThresholds{
+		Default:   handlers.ScoreThresholds{Real: configured.Real, Unverified: configured.Unverified},
+		ByVersion: make(map[string]handlers.ScoreThresholds),
+	}
+	for _, spec := range manifest.Models {
+		if spec.Thresholds != nil {
+			thresholds.ByVersion[spec.Version] = handlers.ScoreThresholds{Real: spec.Thresholds.Real, Unverified: spec.Thresholds.Unverified}
+		}
+	}
+	return thresholds
+}
+
+// getScriptPath returns the configured path to predict.py, defaulting to ml/predict.py under the working directory
+func getScriptPath(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
 	// Get current working directory
 	wd, err := os.Getwd()
 	if err != nil {
-		log.Fatal(err)
+		fatal("Failed to get working directory", err)
 	}
 
 	// Path to ml/predict.py relative to project root
@@ -2,29 +2,38 @@
 """
 FIRE Score Prediction Script
 Loads the trained DistilBERT model and returns FIRE score for article text
+
+Usage:
+    predict.py "<article text>"   score a single article and exit
+    predict.py --server           keep the model loaded and score NDJSON requests from stdin
+
+--model, --base-model and --max-length select the checkpoint to load, the model it was fine-tuned from
+and its window size; they default to model v1.0.0.
 """
 
+import argparse
 import sys
 import json
 import torch
 import os
+import time
 import warnings
-from transformers import AutoTokenizer, DistilBertForSequenceClassification
+from transformers import AutoTokenizer, AutoModelForSequenceClassification
 from transformers import logging as transformers_logging
 
 # Suppress warnings from transformers
 warnings.filterwarnings('ignore')
 transformers_logging.set_verbosity_error()
 
-def load_model(model_path):
-    """Load the trained DistilBERT model"""
+# BASE_MODEL is the model v1.0.0 was fine-tuned from
+BASE_MODEL = 'distilbert-base-uncased'
+
+def load_model(model_path, base_model=BASE_MODEL):
+    """Load the trained checkpoint onto the architecture of base_model"""
     try:
-        # Model configuration from your notebook
-        MODEL_NAME = 'distilbert-base-uncased'
-        
         # Load the model architecture
-        model = DistilBertForSequenceClassification.from_pretrained(
-            MODEL_NAME,
+        model = AutoModelForSequenceClassification.from_pretrained(
+            base_model,
             num_labels=2  # binary classification: fake (0) vs true (1)
         )
         
@@ -38,89 +47,363 @@ def load_model(model_path):
         print(json.dumps({"error": f"Failed to load model: {str(e)}"}), file=sys.stderr)
         sys.exit(1)
 
-def preprocess_text(text, tokenizer, max_length=128):
+# Long articles are scored in overlapping windows of max_length tokens (including [CLS] and [SEP]), MAX_LENGTH
+# unless --max-length is given; consecutive windows share CHUNK_OVERLAP tokens so a sentence cut at a boundary
+# is seen whole in one of them.
+MAX_LENGTH = 128
+CHUNK_OVERLAP = 32
+# MAX_CHUNKS bounds the cost of very long articles; text beyond the last window is not scored
+MAX_CHUNKS = 32
+
+AGGREGATIONS = ("mean", "min", "confidence_weighted")
+
+# HEADLINE_WEIGHT is the share of the overall score given to the headline when a request doesn't set one
+HEADLINE_WEIGHT = 0.3
+
+def token_windows(token_count, max_length=MAX_LENGTH, overlap=CHUNK_OVERLAP, max_chunks=MAX_CHUNKS):
+    """Return the (start, end) token ranges of the overlapping windows covering token_count tokens"""
+    window = max_length - 2  # room for [CLS] and [SEP]
+    step = window - overlap
+    windows = []
+    start = 0
+    while True:
+        windows.append((start, min(start + window, token_count)))
+        if start + window >= token_count or len(windows) == max_chunks:
+            break
+        start += step
+    return windows
+
+def encode_windows(token_id_lists, tokenizer, max_length=MAX_LENGTH):
+    """Add [CLS] and [SEP] to each list of token IDs and pad them into one batch"""
+    inputs = [
+        tokenizer.prepare_for_model(
+            token_ids,
+            add_special_tokens=True,
+            max_length=max_length,
+            padding='max_length',
+            truncation=True,
+            return_attention_mask=True,
+        )
+        for token_ids in token_id_lists
+    ]
+    return {
+        'input_ids': torch.tensor([i['input_ids'] for i in inputs]),
+        'attention_mask': torch.tensor([i['attention_mask'] for i in inputs]),
+    }
+
+def chunk_text(text, tokenizer, max_length=MAX_LENGTH, overlap=CHUNK_OVERLAP, max_chunks=MAX_CHUNKS):
     """
-    Preprocess article text for DistilBERT model
-    Uses the same preprocessing as your training notebook
+    Split article text into overlapping token windows for DistilBERT.
+    Returns the batch encoding, the (start_char, end_char) span of each window in the text,
+    and whether text was left over after max_chunks windows.
     """
-    encoding = tokenizer.encode_plus(
-        text,
-        add_special_tokens=True,
+    encoding = tokenizer(text, add_special_tokens=False, return_offsets_mapping=True)
+    token_ids = encoding['input_ids']
+    offsets = encoding['offset_mapping']
+
+    windows = token_windows(len(token_ids), max_length, overlap, max_chunks)
+    truncated = windows[-1][1] < len(token_ids)
+
+    batch = encode_windows([token_ids[s:e] for s, e in windows], tokenizer, max_length)
+    spans = [(offsets[s][0], offsets[e - 1][1]) if e > s else (0, 0) for s, e in windows]
+    return batch, spans, truncated
+
+def encode_headline(title, tokenizer, max_length=MAX_LENGTH):
+    """Encode the headline as a single window; headlines longer than max_length tokens are cut off"""
+    encoding = tokenizer(
+        title,
         max_length=max_length,
         padding='max_length',
         truncation=True,
         return_attention_mask=True,
-        return_tensors='pt'
+        return_tensors='pt',
     )
-    return encoding
+    return {'input_ids': encoding['input_ids'], 'attention_mask': encoding['attention_mask']}
+
+def score_from_prediction(pred_class, confidence):
+    """
+    Calculate FIRE score (0-100)
+    Higher score = MORE RELIABLE
+    Lower score = LESS RELIABLE
+    """
+    if pred_class == 1:  # True news
+        return int(50 + (confidence * 50))  # Range: 50-100 (safe)
+    return int(50 - (confidence * 50))  # Range: 0-50 (risky)
+
+def aggregate(chunks, method):
+    """
+    Combine chunk scores into the article's score and confidence:
+    mean averages them, min takes the riskiest chunk, and confidence_weighted
+    averages scores weighted by each chunk's confidence.
+    """
+    confidences = [c["confidence"] for c in chunks]
+    mean_confidence = sum(confidences) / len(chunks)
+    if method == "min":
+        riskiest = min(chunks, key=lambda c: c["score"])
+        return riskiest["score"], riskiest["confidence"]
+    if method == "confidence_weighted" and sum(confidences) > 0:
+        weighted = sum(c["score"] * c["confidence"] for c in chunks) / sum(confidences)
+        return int(round(weighted)), mean_confidence
+    return int(round(sum(c["score"] for c in chunks) / len(chunks))), mean_confidence
+
+def classify(model, batch):
+    """Run the model on a batch of windows and return the (score, confidence) of each"""
+    with torch.no_grad():
+        outputs = model(
+            input_ids=batch['input_ids'],
+            attention_mask=batch['attention_mask']
+        )
+
+        probs = torch.softmax(outputs.logits, dim=1)
 
-def predict_fire_score(model, tokenizer, article_text):
+        # Get prediction per window: 0 = fake, 1 = true
+        pred_classes = torch.argmax(probs, dim=1).tolist()
+        results = []
+        for i, pred_class in enumerate(pred_classes):
+            confidence = probs[i][pred_class].item()
+            results.append((score_from_prediction(pred_class, confidence), confidence))
+        return results
+
+def predict_fire_score(model, tokenizer, article, aggregation="mean", headline_weight=HEADLINE_WEIGHT,
+                       max_length=MAX_LENGTH):
     """
-    Run inference using the trained DistilBERT model
-    Returns FIRE score based on model's fake news prediction
+    Run inference using the trained DistilBERT model on an article with title, content, source and author fields.
+    The headline and the body are scored separately and combined as
+    headline_weight * headline_score + (1 - headline_weight) * body_score; an article without a title
+    is scored on its body alone. Returns both sub-scores with the score of every body chunk.
+    Source and author are accepted for models that use them; the current model ignores them.
     """
+    if aggregation not in AGGREGATIONS:
+        return {"error": f"Unknown aggregation {aggregation!r}"}
+    if not 0 <= headline_weight <= 1:
+        return {"error": f"headline_weight must be between 0 and 1, got {headline_weight!r}"}
+
+    title = (article.get("title") or "").strip()
+    content = article.get("content") or ""
+
     try:
-        # Preprocess text using DistilBERT tokenizer
-        encoding = preprocess_text(article_text, tokenizer, max_length=128)
-        
-        # Move to CPU (or GPU if available)
-        input_ids = encoding['input_ids']
-        attention_mask = encoding['attention_mask']
-        
-        # Run inference
-        with torch.no_grad():
+        # Tokenize the headline as one window and split the body into windows using the DistilBERT tokenizer
+        tokenize_start = time.perf_counter()
+        batch, spans, truncated = chunk_text(content, tokenizer, max_length)
+        if title:
+            headline = encode_headline(title, tokenizer, max_length)
+            batch = {key: torch.cat([headline[key], batch[key]]) for key in batch}
+        inference_start = time.perf_counter()
+
+        # Run inference on the headline and every body window at once
+        results = classify(model, batch)
+        inference_end = time.perf_counter()
+
+        if title:
+            (headline_score, headline_confidence), results = results[0], results[1:]
+        chunks = [
+            {
+                "index": i,
+                "start_char": spans[i][0],
+                "end_char": spans[i][1],
+                "score": score,
+                "confidence": confidence,
+            }
+            for i, (score, confidence) in enumerate(results)
+        ]
+        body_score, body_confidence = aggregate(chunks, aggregation)
+
+        fire_score, confidence = body_score, body_confidence
+        if title:
+            fire_score = int(round(headline_weight * headline_score + (1 - headline_weight) * body_score))
+            confidence = headline_weight * headline_confidence + (1 - headline_weight) * body_confidence
+
+        # Step timings let the backend trace where a slow prediction spent its time
+        result = {
+            "overall_score": fire_score,
+            "confidence": confidence,
+            "body_score": body_score,
+            "aggregation": aggregation,
+            "chunks": chunks,
+            "truncated": truncated,
+            "tokenize_ms": (inference_start - tokenize_start) * 1000,
+            "inference_ms": (inference_end - inference_start) * 1000
+        }
+        if title:
+            result["headline_score"] = headline_score
+            result["headline_weight"] = headline_weight
+        return result
+
+    except Exception as e:
+        return {
+            "error": f"Prediction failed: {str(e)}"
+        }
+
+# Explanations hide one word at a time, which costs a forward pass per word, so they cover the headline
+# and only the first EXPLAIN_MAX_CHUNKS windows of the body, running OCCLUSION_BATCH_SIZE passes at once
+EXPLAIN_MAX_CHUNKS = 8
+OCCLUSION_BATCH_SIZE = 64
+# TOP_ATTRIBUTIONS is how many words an explanation returns, strongest first
+TOP_ATTRIBUTIONS = 20
+
+def reliable_probabilities(model, batch):
+    """Return the model's probability that each window is real news"""
+    probabilities = []
+    with torch.no_grad():
+        for i in range(0, len(batch['input_ids']), OCCLUSION_BATCH_SIZE):
             outputs = model(
-                input_ids=input_ids,
-                attention_mask=attention_mask
+                input_ids=batch['input_ids'][i:i + OCCLUSION_BATCH_SIZE],
+                attention_mask=batch['attention_mask'][i:i + OCCLUSION_BATCH_SIZE]
             )
-            
-            logits = outputs.logits
-            probs = torch.softmax(logits, dim=1)
-            
-            # Get prediction: 0 = fake, 1 = true
-            pred_class = torch.argmax(probs, dim=1).item()
-            confidence = probs[0][pred_class].item()
-            
-            # Calculate FIRE score (0-100)
-            # Higher score = MORE RELIABLE 
-            # Lower score = LESS RELIABLE 
-            if pred_class == 1:  # True news
-                fire_score = int(50 + (confidence * 50))  # Range: 50-100 (safe)
-            else:  # Fake news
-                fire_score = int(50 - (confidence * 50))  # Range: 0-50 (risky)
+            probabilities.extend(torch.softmax(outputs.logits, dim=1)[:, 1].tolist())
+    return probabilities
+
+def occlude_words(model, tokenizer, text, field, share, max_chunks, max_length=MAX_LENGTH):
+    """
+    Attribute text's score to its words by occlusion: each word's weight is how much the probability of
+    real news drops when the word is replaced by [MASK], scaled by share, the window's part of the overall score.
+    Positive weights pushed the article towards reliable, negative weights towards fake.
+    Words seen by two overlapping windows get the sum of both weights.
+    Returns the attributions and whether text was left over after max_chunks windows.
+    """
+    encoding = tokenizer(text, add_special_tokens=False, return_offsets_mapping=True)
+    token_ids = encoding['input_ids']
+    offsets = encoding['offset_mapping']
+    word_ids = encoding.word_ids()
+
+    windows = token_windows(len(token_ids), max_length, max_chunks=max_chunks)
+    truncated = windows[-1][1] < len(token_ids)
+    share /= len(windows)
+
+    weights = {}
+    for s, e in windows:
+        # Group the window's tokens into words so word pieces are hidden together
+        words = {}
+        for position in range(s, e):
+            if word_ids[position] is not None:
+                words.setdefault(word_ids[position], []).append(position)
+        if not words:
+            continue
+
+        variants = [token_ids[s:e]]
+        for positions in words.values():
+            occluded = list(token_ids[s:e])
+            for position in positions:
+                occluded[position - s] = tokenizer.mask_token_id
+            variants.append(occluded)
+        probabilities = reliable_probabilities(model, encode_windows(variants, tokenizer, max_length))
+
+        baseline = probabilities[0]
+        for positions, probability in zip(words.values(), probabilities[1:]):
+            span = (offsets[positions[0]][0], offsets[positions[-1]][1])
+            weights[span] = weights.get(span, 0.0) + (baseline - probability) * share
+
+    attributions = [
+        {"field": field, "start_char": start, "end_char": end, "text": text[start:end], "weight": weight}
+        for (start, end), weight in weights.items()
+    ]
+    return attributions, truncated
+
+def explain_fire_score(model, tokenizer, article, headline_weight=HEADLINE_WEIGHT, top_k=TOP_ATTRIBUTIONS,
+                       max_length=MAX_LENGTH):
+    """
+    Explain the FIRE score of an article by word occlusion over its headline and body.
+    Returns the top_k words with the largest absolute weights; body windows share the body's part of the score
+    equally, which matches the mean aggregation and approximates the others.
+    """
+    if not 0 <= headline_weight <= 1:
+        return {"error": f"headline_weight must be between 0 and 1, got {headline_weight!r}"}
 
+    title = (article.get("title") or "").strip()
+    content = article.get("content") or ""
+    body_share = 1 - headline_weight if title else 1
+
+    try:
+        inference_start = time.perf_counter()
+        attributions, truncated = occlude_words(model, tokenizer, content, "content", body_share,
+                                                EXPLAIN_MAX_CHUNKS, max_length)
+        if title:
+            # The headline is scored as a single window, so only its first window is explained
+            headline, _ = occlude_words(model, tokenizer, title, "title", headline_weight, 1, max_length)
+            attributions += headline
+        inference_end = time.perf_counter()
+
+        attributions.sort(key=lambda a: abs(a["weight"]), reverse=True)
         return {
-            "overall_score": fire_score,
-            "confidence": confidence
+            "method": "occlusion",
+            "attributions": attributions[:top_k],
+            "truncated": truncated,
+            "inference_ms": (inference_end - inference_start) * 1000
         }
-    
+
     except Exception as e:
         return {
-            "error": f"Prediction failed: {str(e)}"
+            "error": f"Explanation failed: {str(e)}"
         }
 
+def serve(model, tokenizer, max_length=MAX_LENGTH):
+    """
+    Server mode: read newline-delimited JSON requests from stdin and write one
+    JSON response per line to stdout. Requests look like
+    {"id": 1, "article": {"title": "...", "content": "...", "source": "...", "author": "..."},
+     "aggregation": "mean", "headline_weight": 0.3}
+    and each response echoes the request id. Requests with only "text" are scored as an untitled article.
+    Requests with "explain": true return the article's word attributions instead of a score.
+    The model stays loaded between requests.
+    """
+    # Tell the Go worker pool the model is loaded
+    print(json.dumps({"ready": True}), flush=True)
+
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+
+        try:
+            request = json.loads(line)
+        except json.JSONDecodeError as e:
+            print(json.dumps({"error": f"Invalid request: {str(e)}"}), flush=True)
+            continue
+
+        article = request.get("article") or {"content": request.get("text", "")}
+        headline_weight = request.get("headline_weight")
+        if headline_weight is None:
+            headline_weight = HEADLINE_WEIGHT
+        if request.get("explain"):
+            result = explain_fire_score(model, tokenizer, article, headline_weight,
+                                        request.get("top_k") or TOP_ATTRIBUTIONS, max_length)
+        else:
+            result = predict_fire_score(model, tokenizer, article, request.get("aggregation") or "mean",
+                                        headline_weight, max_length)
+        result["id"] = request.get("id")
+        print(json.dumps(result), flush=True)
+
 def main():
     """Main entry point"""
-    if len(sys.argv) < 2:
+    parser = argparse.ArgumentParser(description="Score articles with a FIRE model")
+    parser.add_argument("text", nargs="?", help="article text to score once")
+    parser.add_argument("--server", action="store_true",
+                        help="keep the model loaded and score NDJSON requests from stdin")
+    # The v1.0.0 checkpoint is in the same directory as this script
+    parser.add_argument("--model", default=os.path.join(os.path.dirname(__file__), "bestmodel_3_run5.pt"),
+                        help="fine-tuned checkpoint to load")
+    parser.add_argument("--base-model", default=BASE_MODEL,
+                        help="Hugging Face model the checkpoint was fine-tuned from")
+    parser.add_argument("--max-length", type=int, default=MAX_LENGTH,
+                        help="tokens per window, including [CLS] and [SEP]")
+    args = parser.parse_args()
+    if not args.server and args.text is None:
         print(json.dumps({"error": "No article text provided"}))
         sys.exit(1)
-    
-    # Get article text from command line argument
-    article_text = sys.argv[1]
-    
-    # Path to the trained model (in the same directory as this script)
-    model_path = os.path.join(os.path.dirname(__file__), "bestmodel_3_run5.pt")
-    
+
     # Load tokenizer (same as used in training)
-    MODEL_NAME = 'distilbert-base-uncased'
-    tokenizer = AutoTokenizer.from_pretrained(MODEL_NAME)
-    
+    tokenizer = AutoTokenizer.from_pretrained(args.base_model)
+
     # Load model
-    model = load_model(model_path)
-    
+    model = load_model(args.model, args.base_model)
+
+    if args.server:
+        serve(model, tokenizer, args.max_length)
+        return
+
     # Get prediction
-    result = predict_fire_score(model, tokenizer, article_text)
-    
+    result = predict_fire_score(model, tokenizer, {"content": args.text}, max_length=args.max_length)
+
     # Output JSON to stdout (Go will capture this)
     print(json.dumps(result))
 
@@ -1,4 +1,5 @@
 import React, { useEffect, useState } from 'react';
+import axios from 'axios';
 import { useParams, useNavigate } from 'react-router-dom';
 import { Layout } from '../components/common/Layout';
 import { FIREBadge } from '../components/common/FIREBadge';
@@ -51,6 +52,11 @@ export const ArticlePage: React.FC = () => {
       alert('Article reported successfully! Moderators will review it.');
       setShowReportModal(false);
     } catch (err) {
+      if (axios.isAxiosError(err) && err.response?.status === 409) {
+        alert('You have already reported this article.');
+        setShowReportModal(false);
+        return;
+      }
       alert('Failed to submit report. Please try again.');
       console.error('Error submitting report:', err);
     } finally {
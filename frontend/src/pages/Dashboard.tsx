@@ -9,6 +9,8 @@ export const Dashboard: React.FC = () => {
   const [articles, setArticles] = useState<Article[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
+  const [nextPageToken, setNextPageToken] = useState<string | undefined>();
+  const [loadingMore, setLoadingMore] = useState(false);
 
   useEffect(() => {
     loadArticles();
@@ -19,13 +21,14 @@ export const Dashboard: React.FC = () => {
       setLoading(true);
       setError(null);
       console.log('Loading articles from API...');
-      const data = await articleService.getArticles();
-      console.log('Received articles:', data);
-      console.log('Number of articles:', data.length);
-      if (data.length > 0) {
-        console.log('First article:', data[0]);
+      const page = await articleService.getArticles();
+      console.log('Received articles:', page.articles);
+      console.log('Number of articles:', page.articles.length);
+      if (page.articles.length > 0) {
+        console.log('First article:', page.articles[0]);
       }
-      setArticles(data);
+      setArticles(page.articles);
+      setNextPageToken(page.nextPageToken);
     } catch (err) {
       setError('Failed to load articles. Please try again later.');
       console.error('Error loading articles:', err);
@@ -34,6 +37,21 @@ export const Dashboard: React.FC = () => {
     }
   };
 
+  const loadMoreArticles = async () => {
+    if (!nextPageToken) return;
+    try {
+      setLoadingMore(true);
+      const page = await articleService.getArticles(nextPageToken);
+      setArticles((current) => [...current, ...page.articles]);
+      setNextPageToken(page.nextPageToken);
+    } catch (err) {
+      setError('Failed to load more articles. Please try again later.');
+      console.error('Error loading more articles:', err);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
   return (
     <Layout>
       <div className="space-y-6">
@@ -72,6 +90,17 @@ export const Dashboard: React.FC = () => {
             {articles.map((article) => (
               <ArticleCard key={article.id} article={article} />
             ))}
+            {nextPageToken && (
+              <div className="text-center">
+                <button
+                  onClick={loadMoreArticles}
+                  disabled={loadingMore}
+                  className="px-4 py-2 bg-blue-600 text-white rounded-md hover:bg-blue-700 transition-colors disabled:opacity-50"
+                >
+                  {loadingMore ? 'Loading...' : 'Load more'}
+                </button>
+              </div>
+            )}
           </div>
         )}
       </div>
@@ -1,11 +1,14 @@
 import React, { useEffect, useState } from 'react';
+import axios from 'axios';
 import { Layout } from '../components/common/Layout';
 import { LoadingSpinner } from '../components/common/LoadingSpinner';
 import { Article } from '../types';
 import { moderatorService } from '../services/moderatorService';
+import { useAuth } from '../context/AuthContext';
 import { Link } from 'react-router-dom';
 
 export const ModeratorConsole: React.FC = () => {
+  const { user } = useAuth();
   const [queue, setQueue] = useState<Article[]>([]);
   const [loading, setLoading] = useState(true);
   const [selectedArticle, setSelectedArticle] = useState<string | null>(null);
@@ -13,6 +16,7 @@ export const ModeratorConsole: React.FC = () => {
   const [confidence, setConfidence] = useState<number>(0.8);
   const [notes, setNotes] = useState('');
   const [submitting, setSubmitting] = useState(false);
+  const [error, setError] = useState<string | null>(null);
 
   useEffect(() => {
     loadQueue();
@@ -21,10 +25,16 @@ export const ModeratorConsole: React.FC = () => {
   const loadQueue = async () => {
     try {
       setLoading(true);
+      setError(null);
       const data = await moderatorService.getQueue();
       console.log('Moderation queue:', data);
       setQueue(data);
     } catch (err) {
+      if (axios.isAxiosError(err) && err.response?.status === 403) {
+        setError('Your account does not have the moderator role. Ask an admin to grant it.');
+      } else {
+        setError('Failed to load the moderation queue. Please try again later.');
+      }
       console.error('Error loading queue:', err);
     } finally {
       setLoading(false);
@@ -32,7 +42,7 @@ export const ModeratorConsole: React.FC = () => {
   };
 
   const handleOverride = async () => {
-    if (!selectedArticle) return;
+    if (!selectedArticle || !user) return;
 
     try {
       setSubmitting(true);
@@ -41,6 +51,7 @@ export const ModeratorConsole: React.FC = () => {
         new_label: overrideLabel,
         confidence: confidence,
         notes,
+        moderator_id: user.email ?? user.uid,
       });
       alert('Override saved successfully!');
       setSelectedArticle(null);
@@ -73,7 +84,13 @@ export const ModeratorConsole: React.FC = () => {
 
         {loading && <LoadingSpinner message="Loading moderation queue..." />}
 
-        {!loading && queue.length === 0 && (
+        {error && (
+          <div className="bg-red-50 border border-red-200 text-red-700 px-4 py-3 rounded-md">
+            {error}
+          </div>
+        )}
+
+        {!loading && !error && queue.length === 0 && (
           <div className="bg-white rounded-lg shadow p-8 text-center">
             <p className="text-gray-600 text-lg">✅ No articles in moderation queue!</p>
             <p className="text-gray-500 mt-2">All clear for now.</p>
@@ -110,7 +127,14 @@ export const ModeratorConsole: React.FC = () => {
                         {article.title}
                       </Link>
                     </td>
-                    <td className="px-6 py-4 text-sm text-gray-600">{article.source}</td>
+                    <td className="px-6 py-4 text-sm text-gray-600">
+                      {article.source}
+                      {article.report_count ? (
+                        <div className="text-xs text-red-600 mt-1">
+                          {article.report_count} {article.report_count === 1 ? 'report' : 'reports'}
+                        </div>
+                      ) : null}
+                    </td>
                     <td className="px-6 py-4">
                       {article.fire_score && (
                         <>
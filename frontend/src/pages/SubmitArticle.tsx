@@ -56,6 +56,10 @@ export const SubmitArticle: React.FC = () => {
       // Provide more specific error messages
       if (err.code === 'ECONNABORTED' || err.message?.includes('timeout')) {
         setError('Request timed out. The article may still be processing. Please check the news feed in a moment.');
+      } else if (err.response?.status === 401) {
+        setError('Please sign in with a partner account to submit articles.');
+      } else if (err.response?.status === 403) {
+        setError('Your account is not allowed to submit articles. Ask an admin for the partner role.');
       } else if (err.response?.status === 500) {
         setError('Server error. Please try again or contact support.');
       } else if (err.response?.data?.message) {
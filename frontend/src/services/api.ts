@@ -1,4 +1,5 @@
 import axios from 'axios';
+import { auth } from '../config/firebase';
 
 const API_BASE_URL = import.meta.env.VITE_API_URL || '/api/v1';
 
@@ -10,10 +11,10 @@ export const api = axios.create({
   },
 });
 
-// Add request interceptor for auth tokens (if needed later)
+// Attach the signed-in user's Firebase ID token; getIdToken refreshes it when it is about to expire
 api.interceptors.request.use(
-  (config) => {
-    const token = localStorage.getItem('auth_token');
+  async (config) => {
+    const token = await auth.currentUser?.getIdToken();
     if (token) {
       config.headers.Authorization = `Bearer ${token}`;
     }
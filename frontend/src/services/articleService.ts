@@ -1,11 +1,16 @@
 import { api } from './api';
-import { Article, CreateArticleRequest } from '../types';
+import { Article, ArticlePage, CreateArticleRequest, ReportCategory, ReportRequest } from '../types';
 
 export const articleService = {
-  // Get all articles with FIRE scores
-  async getArticles(): Promise<Article[]> {
-    const response = await api.get<Article[]>('/articles');
-    return response.data;
+  // Get a page of articles with FIRE scores; pass nextPageToken from the previous page to continue
+  async getArticles(pageToken?: string): Promise<ArticlePage> {
+    const response = await api.get<Article[]>('/articles', {
+      params: pageToken ? { page_token: pageToken } : undefined,
+    });
+    return {
+      articles: response.data,
+      nextPageToken: response.headers['x-next-page-token'] as string | undefined,
+    };
   },
 
   // Get single article by ID
@@ -21,7 +26,8 @@ export const articleService = {
   },
 
   // Report an article
-  async reportArticle(articleId: string, reason: string): Promise<void> {
-    await api.post(`/articles/${articleId}/report`, { reason });
+  async reportArticle(articleId: string, reason: string, category: ReportCategory = 'incorrect_score'): Promise<void> {
+    const body: ReportRequest = { reason, category };
+    await api.post(`/articles/${articleId}/report`, body);
   },
 };
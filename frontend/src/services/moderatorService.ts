@@ -2,10 +2,18 @@ import { api } from './api';
 import { Article, ModeratorOverrideRequest } from '../types';
 
 export const moderatorService = {
-  // Get moderation queue (articles reported by users)
+  // Get the full moderation queue (articles reported by users), following every page
   async getQueue(): Promise<Article[]> {
-    const response = await api.get<Article[]>('/moderator/queue');
-    return response.data;
+    const queue: Article[] = [];
+    let pageToken: string | undefined;
+    do {
+      const response = await api.get<Article[]>('/moderator/queue', {
+        params: pageToken ? { page_token: pageToken } : undefined,
+      });
+      queue.push(...response.data);
+      pageToken = response.headers['x-next-page-token'] as string | undefined;
+    } while (pageToken);
+    return queue;
   },
 
   // Override FIRE score
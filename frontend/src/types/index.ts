@@ -9,7 +9,15 @@ export interface Article {
   url?: string;
   publishedAt?: Date;
   model_version?: string;
+  model_score?: number; // the model's original score; fire_score reflects moderator overrides
   fire_score?: FIREScore;
+  report_count?: number; // returned by the moderation queue
+  latest_report_at?: string;
+}
+
+export interface ArticlePage {
+  articles: Article[];
+  nextPageToken?: string; // present when more articles are available
 }
 
 export interface FIREScore {
@@ -29,9 +37,11 @@ export interface CreateArticleRequest {
   publishedAt: string;
 }
 
+export type ReportCategory = 'incorrect_score' | 'misleading' | 'satire' | 'spam' | 'other';
+
 export interface ReportRequest {
-  article_id: string;
-  reason: string;
+  reason?: string;
+  category?: ReportCategory;
 }
 
 export interface ModeratorOverrideRequest {
@@ -39,6 +49,7 @@ export interface ModeratorOverrideRequest {
   new_label: 'fake' | 'real';
   confidence: number;
   notes?: string;
+  moderator_id: string; // recorded in the article's override history
 }
 
 export interface ModeratorQueueItem {